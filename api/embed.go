@@ -0,0 +1,13 @@
+package api
+
+import (
+	_ "embed"
+)
+
+//go:embed swagger.yaml
+var spec []byte
+
+// GetSpec 返回嵌入的 OpenAPI 文档
+func GetSpec() []byte {
+	return spec
+}
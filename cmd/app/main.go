@@ -8,6 +8,15 @@ import (
 )
 
 func main() {
+	// routes 子命令输出路由表后退出
+	if len(os.Args) > 1 && os.Args[1] == "routes" {
+		if err := runRoutes(os.Args[2:]); err != nil {
+			fmt.Printf("Routes error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// 获取配置文件路径
 	configFile := os.Getenv("CONFIG_FILE")
 	if configFile == "" {
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"go-api-mono/internal/app"
+)
+
+// runRoutes 执行 routes 子命令，以表格或 JSON 格式输出路由表
+func runRoutes(args []string) error {
+	fs := flag.NewFlagSet("routes", flag.ExitOnError)
+	format := fs.String("format", "table", "output format (table or json)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	table, err := app.RouteTable()
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(table)
+	case "table":
+		return writeRouteTable(os.Stdout, table)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+// writeRouteTable 以对齐的表格输出路由表
+func writeRouteTable(w io.Writer, table []app.RouteInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tMETHOD\tPATH\tHANDLER\tSECURITY\tRATE LIMIT\tMIDDLEWARE")
+	for _, r := range table {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Server, r.Method, r.Path, r.Handler,
+			orDash(strings.Join(r.Security, ",")), orDash(r.RateLimit), strings.Join(r.Middleware, " > "))
+	}
+	return tw.Flush()
+}
+
+// orDash 空值以 "-" 显示
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -2,24 +2,31 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	"go-api-mono/internal/app/user/controller"
-	"go-api-mono/internal/app/user/repository"
-	"go-api-mono/internal/app/user/service"
 	"go-api-mono/internal/pkg/auth"
+	"go-api-mono/internal/pkg/concurrency"
 	"go-api-mono/internal/pkg/config"
 	"go-api-mono/internal/pkg/core"
 	"go-api-mono/internal/pkg/database"
+	"go-api-mono/internal/pkg/health"
 	"go-api-mono/internal/pkg/http/middleware"
+	"go-api-mono/internal/pkg/idempotency"
+	"go-api-mono/internal/pkg/lifecycle"
+	"go-api-mono/internal/pkg/listener"
 	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/metrics"
+	"go-api-mono/internal/pkg/openapi"
+	"go-api-mono/internal/pkg/reporting"
 	"go-api-mono/internal/pkg/security"
+	"go-api-mono/internal/pkg/tracing"
 
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
@@ -34,11 +41,45 @@ type Options struct {
 type App struct {
 	config  *config.Config
 	logger  *logger.Logger
-	mux     *http.ServeMux
 	db      *database.DB
-	server  *http.Server
+	server  *core.Server
+	admin   *core.Server // 管理服务器，未配置管理端口时为nil
 	jwt     *auth.JWT
-	limiter *security.IPRateLimiter
+	limiter security.RateLimiter
+	redis   *redis.Client // 限流使用 Redis 存储时的客户端，否则为nil
+	idem    idempotency.Store
+	// concurrency 自适应并发限制器，未启用时为nil
+	concurrency *concurrency.Limiter
+
+	validator *openapi.Validator
+	health    *health.Registry
+	metrics   *metrics.Metrics
+	cors      *corsPolicies
+	sessions  *auth.Sessions
+	// rateLimits 按路由绑定的限流策略，重新加载配置时更新
+	rateLimits *middleware.RateLimitRules
+	// bodyCapture 请求体与响应体的记录目标，重新加载配置时更新
+	bodyCapture *middleware.BodyCaptureRules
+
+	// shutdownTracing 刷新并关闭链路导出器
+	shutdownTracing func(context.Context) error
+	// reporter 恢复的 panic 的上报器，sentry 为其中的 Sentry 上报器，未配置时为nil
+	reporter reporting.Reporter
+	sentry   *reporting.SentryReporter
+
+	// configs 当前生效的配置，重新加载时通知订阅的组件；config 为启动时加载的配置
+	configs *config.Store
+	// lifecycle 按顺序启动与停止各组件
+	lifecycle *lifecycle.Manager
+	// tasks 关闭时需要等待的后台任务与长连接
+	tasks *lifecycle.Tasks
+	// serveErrs 服务器在运行中出错时的错误
+	serveErrs chan error
+	// inherited 从 systemd 或升级前的进程继承的监听器，listeners 为服务器正在使用的监听器，均按 api、admin 索引
+	inherited map[string]net.Listener
+	listeners map[string]net.Listener
+	// upgraded 监听器是否已交给升级后的新进程
+	upgraded bool
 }
 
 // New 创建新的应用程序实例
@@ -66,6 +107,28 @@ func New(opts Options) (*App, error) {
 	}
 	app.logger = log
 
+	// 初始化链路追踪
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Enabled:        cfg.Tracing.Enabled,
+		ServiceName:    cfg.App.Name,
+		ServiceVersion: cfg.App.Version,
+		Environment:    cfg.App.Mode,
+		Endpoint:       cfg.Tracing.Endpoint,
+		Insecure:       cfg.Tracing.Insecure,
+		SampleRatio:    cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	app.shutdownTracing = shutdownTracing
+
+	// 创建错误上报器
+	reporter, err := app.newReporter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create error reporter: %w", err)
+	}
+	app.reporter = reporter
+
 	// 创建数据库连接
 	db, err := database.New(database.DatabaseConfig{
 		Host:         cfg.Database.Host,
@@ -93,77 +156,78 @@ func New(opts Options) (*App, error) {
 	app.jwt = jwt
 
 	// 创建速率限制器
-	limiter := security.NewIPRateLimiter(float64(cfg.RateLimit.Requests), float64(cfg.RateLimit.Burst))
+	limiter, err := app.newRateLimiter()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rate limiter: %w", err)
+	}
 	app.limiter = limiter
 
+	// 创建并发限制器
+	app.concurrency = newConcurrencyLimiter(cfg.Concurrency)
+
+	// 创建幂等记录存储
+	switch cfg.Idempotency.Store {
+	case "database":
+		app.idem = idempotency.NewDBStore(db)
+	default:
+		app.idem = idempotency.NewMemoryStore()
+	}
+
+	// 创建健康检查注册表
+	app.health = health.NewRegistry(cfg.Health.Timeout, cfg.Health.CacheTTL)
+	app.health.Register("database", db, health.CheckOptions{})
+
+	// 创建监控指标
+	if cfg.Metrics.Enabled {
+		app.metrics = metrics.New()
+		sqlDB, err := db.DB.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database instance: %w", err)
+		}
+		if err := app.metrics.RegisterDB(cfg.Database.Database, sqlDB); err != nil {
+			return nil, fmt.Errorf("failed to register database metrics: %w", err)
+		}
+		if memory, ok := limiter.(*security.MemoryRateLimiter); ok {
+			if err := app.metrics.RegisterRateLimiter(memory); err != nil {
+				return nil, fmt.Errorf("failed to register rate limiter metrics: %w", err)
+			}
+		}
+		if app.concurrency != nil {
+			if err := app.metrics.RegisterConcurrencyLimiter(app.concurrency); err != nil {
+				return nil, fmt.Errorf("failed to register concurrency limiter metrics: %w", err)
+			}
+		}
+	}
+
 	// 创建HTTP服务器
-	mux := http.NewServeMux()
-	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:      mux,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
-		IdleTimeout:  cfg.Server.ShutdownTimeout,
+	trustedProxies, err := core.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse trusted proxies: %w", err)
 	}
-	app.server = server
-	app.mux = mux
+	app.server = core.NewServer(core.ServerOptions{
+		Port:           cfg.Server.Port,
+		ReadTimeout:    cfg.Server.ReadTimeout,
+		WriteTimeout:   cfg.Server.WriteTimeout,
+		IdleTimeout:    cfg.Server.ShutdownTimeout,
+		Logger:         log,
+		TrustedProxies: trustedProxies,
+	})
+
+	// 创建管理服务器，仅在配置了独立端口时启用
+	app.admin = newAdminServer(cfg, log)
 
 	// 初始化路由
 	if err := app.initRoutes(); err != nil {
 		return nil, fmt.Errorf("failed to initialize routes: %w", err)
 	}
 
-	return app, nil
-}
-
-// initRoutes 初始化路由
-func (a *App) initRoutes() error {
-	// 创建用户仓储
-	userRepo := repository.NewUserRepository(a.db)
-
-	// 创建用户服务
-	userService := service.NewUserService(userRepo)
-
-	// 创建用户控制器
-	userController := controller.NewUserController(userService, a.jwt)
-
-	// 创建中间件链
-	chain := middleware.Chain(
-		middleware.Recovery(a.logger),
-		middleware.Logger(a.logger),
-		middleware.RequestID(),
-		middleware.CORS(),
-	)
-
-	// 创建需要认证的中间件链
-	protectedChain := middleware.Chain(
-		middleware.Recovery(a.logger),
-		middleware.Logger(a.logger),
-		middleware.RequestID(),
-		middleware.CORS(),
-		middleware.JWT(a.jwt, middleware.DefaultJWTOptions),
-		middleware.RateLimit(a.limiter, middleware.DefaultRateLimitOptions),
-	)
-
-	// 注册路由
-	a.mux.HandleFunc("POST /api/v1/auth/login", a.wrapHandler(userController.Login, chain))
-	a.mux.HandleFunc("POST /api/v1/auth/register", a.wrapHandler(userController.Register, chain))
-	a.mux.HandleFunc("GET /api/v1/users", a.wrapHandler(userController.List, protectedChain))
-	a.mux.HandleFunc("GET /api/v1/users/{id}", a.wrapHandler(userController.Get, protectedChain))
-	a.mux.HandleFunc("PUT /api/v1/users/{id}", a.wrapHandler(userController.Update, protectedChain))
-	a.mux.HandleFunc("DELETE /api/v1/users/{id}", a.wrapHandler(userController.Delete, protectedChain))
+	// 订阅可重新加载的配置项
+	app.initReload()
 
-	return nil
-}
+	// 注册各组件的启动与停止钩子
+	app.initLifecycle()
 
-// wrapHandler 包装控制器处理函数
-func (a *App) wrapHandler(h func(*core.Context), m middleware.Middleware) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := core.NewContext(r, w, a.logger)
-		m(func(w http.ResponseWriter, r *http.Request) {
-			h(ctx)
-		})(w, r)
-	}
+	return app, nil
 }
 
 // Run 运行应用程序
@@ -176,65 +240,71 @@ func Run(opts Options) error {
 	return app.Run()
 }
 
-// Run 运行应用程序
+// Run 运行应用程序，收到退出信号或服务器出错时优雅关闭
 func (a *App) Run() error {
-	// 创建错误通道
-	errChan := make(chan error, 1)
-
-	// 启动HTTP服务器
-	go func() {
-		a.logger.Info("Starting server", zap.String("address", a.server.Addr))
-		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errChan <- fmt.Errorf("failed to start server: %w", err)
-		}
-	}()
-
-	// 等待信号
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	select {
-	case err := <-errChan:
+	// 按顺序启动各组件，失败时已启动的组件会被停止
+	if err := a.lifecycle.Start(context.Background()); err != nil {
 		return err
-	case <-quit:
-		a.logger.Info("Shutting down server...")
-
-		// 创建关闭上下文
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+	}
 
-		// 关闭HTTP服务器
-		if err := a.server.Shutdown(ctx); err != nil {
-			return fmt.Errorf("failed to shutdown server: %w", err)
-		}
+	// 等待退出信号
+	err := a.wait(a.serveErrs)
+	if err != nil {
+		a.logger.Error("Server failed", zap.Error(err))
+	}
 
-		// 关闭数据库连接
-		if err := a.db.Close(); err != nil {
-			return fmt.Errorf("failed to close database connection: %w", err)
-		}
+	// 服务器出错时同样关闭其余组件并释放资源
+	return errors.Join(err, a.Stop())
+}
 
-		a.logger.Info("Server stopped")
+// wait 等待退出信号或服务器错误，收到 SIGHUP 时重新加载配置
+// 收到 listener.UpgradeSignal 时启动新的可执行文件，新进程就绪后返回以便优雅关闭
+func (a *App) wait(errChan <-chan error) error {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+	upgrade := make(chan os.Signal, 1)
+	if listener.UpgradeSignal != nil {
+		signal.Notify(upgrade, listener.UpgradeSignal)
+		defer signal.Stop(upgrade)
 	}
 
-	return nil
+	for {
+		select {
+		case err := <-errChan:
+			return err
+		case <-reload:
+			if err := a.ReloadConfig(); err != nil {
+				a.logger.Error("Failed to reload config", zap.Error(err))
+			}
+		case <-upgrade:
+			if err := a.upgrade(); err != nil {
+				a.logger.Error("Upgrade failed, keep serving", zap.Error(err))
+				continue
+			}
+			return nil
+		case <-quit:
+			return nil
+		}
+	}
 }
 
-// Stop 停止应用程序
+// Stop 优雅关闭应用程序，所有步骤共享 ShutdownTimeout 的时长上限
 func (a *App) Stop() error {
-	// 创建关闭上下文
+	a.logger.Info("Shutting down server...",
+		zap.Duration("timeout", a.config.Server.ShutdownTimeout),
+		zap.Duration("drain_delay", a.config.Server.DrainDelay))
+
 	ctx, cancel := context.WithTimeout(context.Background(), a.config.Server.ShutdownTimeout)
 	defer cancel()
 
-	// 关闭HTTP服务器
-	if err := a.server.Shutdown(ctx); err != nil {
-		return fmt.Errorf("failed to shutdown server: %w", err)
-	}
-
-	// 关闭数据库连接
-	if err := a.db.Close(); err != nil {
-		return fmt.Errorf("failed to close database connection: %w", err)
+	if err := a.lifecycle.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to shutdown gracefully: %w", err)
 	}
 
+	a.logger.Info("Server stopped")
 	return nil
 }
 
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"go-api-mono/internal/pkg/config"
+	"go-api-mono/internal/pkg/http/middleware"
+
+	"go.uber.org/zap"
+)
+
+// bodyCaptureTargets 将配置中的记录目标转换为中间件目标
+func bodyCaptureTargets(cfg config.BodyCaptureConfig) middleware.BodyCaptureTargets {
+	return middleware.BodyCaptureTargets{
+		Routes:  cfg.Routes,
+		Users:   cfg.Users,
+		APIKeys: cfg.APIKeys,
+	}
+}
+
+// bodyCaptureMiddleware 返回请求体与响应体记录中间件
+func (a *App) bodyCaptureMiddleware() middleware.Middleware {
+	opts := middleware.DefaultBodyCaptureOptions
+	opts.MaxSize = a.config.BodyCapture.MaxSize
+	opts.Redact = a.config.BodyCapture.Redact
+	opts.APIKeyHeader = a.config.BodyCapture.APIKeyHeader
+	return middleware.BodyCapture(a.logger, a.bodyCapture, opts)
+}
+
+// applyBodyCapture 按重新加载的配置替换请求体记录目标
+// 记录大小上限与隐藏字段需要重启才能生效
+func (a *App) applyBodyCapture(cfg *config.Config) error {
+	a.bodyCapture.Update(bodyCaptureTargets(cfg.BodyCapture))
+	a.logger.Info("Body capture targets reloaded",
+		zap.Int("routes", len(cfg.BodyCapture.Routes)),
+		zap.Int("users", len(cfg.BodyCapture.Users)),
+		zap.Int("api_keys", len(cfg.BodyCapture.APIKeys)))
+	return nil
+}
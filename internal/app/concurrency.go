@@ -0,0 +1,38 @@
+package app
+
+import (
+	"go-api-mono/internal/pkg/concurrency"
+	"go-api-mono/internal/pkg/config"
+	"go-api-mono/internal/pkg/http/middleware"
+)
+
+// newConcurrencyLimiter 按配置创建自适应并发限制器，未启用时返回nil
+func newConcurrencyLimiter(cfg config.ConcurrencyConfig) *concurrency.Limiter {
+	if !cfg.Enabled {
+		return nil
+	}
+	return concurrency.NewLimiter(concurrency.Options{
+		InitialLimit:     cfg.InitialLimit,
+		MinLimit:         cfg.MinLimit,
+		MaxLimit:         cfg.MaxLimit,
+		LatencyThreshold: cfg.LatencyThreshold,
+		BackoffRatio:     cfg.BackoffRatio,
+		LowPriorityShare: cfg.LowPriorityShare,
+	})
+}
+
+// concurrencyMiddleware 返回自适应并发限制中间件，未启用时返回空中间件
+func (a *App) concurrencyMiddleware() middleware.Middleware {
+	if a.concurrency == nil {
+		return middleware.Noop
+	}
+
+	opts := middleware.DefaultConcurrencyOptions
+	opts.RetryAfter = a.config.Concurrency.RetryAfter
+	opts.Routes = make(map[string]concurrency.Priority, len(a.config.Concurrency.Routes))
+	for _, r := range a.config.Concurrency.Routes {
+		// 优先级已在加载配置时校验
+		opts.Routes[r.Route], _ = concurrency.ParsePriority(r.Priority)
+	}
+	return middleware.ConcurrencyLimit(a.concurrency, opts)
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"go-api-mono/internal/pkg/config"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/http/middleware"
+)
+
+// corsPolicies 跨域策略集合
+type corsPolicies struct {
+	defaults  middleware.CORSOptions
+	overrides map[string]middleware.CORSOptions // 路由组前缀 -> 策略选项
+	// groups 已注册的路由组前缀 -> 策略，重新加载时就地更新
+	groups map[string]*middleware.CORSPolicy
+}
+
+// newCORSPolicies 根据配置生成默认策略与各路由组的覆盖策略，并校验能否编译
+func newCORSPolicies(cfg config.CORSConfig) (*corsPolicies, error) {
+	p := &corsPolicies{
+		defaults:  corsOptions(cfg.CORSPolicyConfig),
+		overrides: make(map[string]middleware.CORSOptions, len(cfg.Overrides)),
+		groups:    make(map[string]*middleware.CORSPolicy),
+	}
+	if _, err := middleware.NewCORSPolicy(p.defaults); err != nil {
+		return nil, err
+	}
+	for prefix, override := range cfg.Overrides {
+		opts := corsOptions(override)
+		if _, err := middleware.NewCORSPolicy(opts); err != nil {
+			return nil, fmt.Errorf("cors override %s: %w", prefix, err)
+		}
+		p.overrides[strings.TrimSuffix(prefix, "/")] = opts
+	}
+	return p, nil
+}
+
+// corsOptions 将配置转换为中间件选项，未配置的方法、请求头与缓存时间使用默认值
+// 未配置来源时不允许任何跨域请求
+func corsOptions(cfg config.CORSPolicyConfig) middleware.CORSOptions {
+	opts := middleware.CORSOptions{
+		AllowOrigins:     cfg.AllowOrigins,
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
+	if len(opts.AllowMethods) == 0 {
+		opts.AllowMethods = middleware.DefaultCORSOptions.AllowMethods
+	}
+	if len(opts.AllowHeaders) == 0 {
+		opts.AllowHeaders = middleware.DefaultCORSOptions.AllowHeaders
+	}
+	if len(opts.ExposeHeaders) == 0 {
+		opts.ExposeHeaders = middleware.DefaultCORSOptions.ExposeHeaders
+	}
+	if opts.MaxAge == 0 {
+		opts.MaxAge = middleware.DefaultCORSOptions.MaxAge
+	}
+	return opts
+}
+
+// options 返回路由组前缀对应的策略选项，按最长前缀匹配覆盖策略
+func (p *corsPolicies) options(prefix string) middleware.CORSOptions {
+	opts, matched := p.defaults, -1
+	for candidate, override := range p.overrides {
+		if len(candidate) > matched && hasPathPrefix(prefix, candidate) {
+			opts, matched = override, len(candidate)
+		}
+	}
+	return opts
+}
+
+// forPrefix 返回路由组前缀对应的策略，相同前缀的路由组共用同一个策略
+func (p *corsPolicies) forPrefix(prefix string) *middleware.CORSPolicy {
+	if policy, ok := p.groups[prefix]; ok {
+		return policy
+	}
+	// 选项已在创建时校验
+	policy, _ := middleware.NewCORSPolicy(p.options(prefix))
+	p.groups[prefix] = policy
+	return policy
+}
+
+// update 按新的配置更新已注册路由组的策略，新配置无效时保留原有策略
+// 新增的覆盖前缀同样按最长前缀匹配作用于已注册的路由组
+func (p *corsPolicies) update(cfg config.CORSConfig) error {
+	next, err := newCORSPolicies(cfg)
+	if err != nil {
+		return err
+	}
+	for prefix, policy := range p.groups {
+		if err := policy.Update(next.options(prefix)); err != nil {
+			return fmt.Errorf("cors policy for %q: %w", prefix, err)
+		}
+	}
+	p.defaults, p.overrides = next.defaults, next.overrides
+	return nil
+}
+
+// hasPathPrefix 判断路径是否以按段划分的前缀开头，如 /api/v1 匹配 /api/v1/users 而不匹配 /api/v10
+func hasPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || path[len(prefix)] == '/' || strings.HasSuffix(prefix, "/")
+}
+
+// useCORS 为路由组添加跨域中间件，需在注册路由之前调用，且位于认证等中间件之前以便应答预检请求
+func (a *App) useCORS(g *core.Group) {
+	g.UseNamed(middleware.Named(middleware.CORS(a.cors.forPrefix(g.Prefix())))...)
+}
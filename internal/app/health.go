@@ -0,0 +1,36 @@
+package app
+
+import (
+	"net/http"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/health"
+)
+
+// registerHealthRoutes 注册存活与就绪检查路由
+func (a *App) registerHealthRoutes() {
+	probes := a.server.Group("")
+	a.useCORS(probes)
+	probes.Handle("GET", "/healthz", health.LivenessHandler(a.health, a.isAdmin),
+		core.Summary("存活检查", "管理员令牌可查看检查明细"),
+		core.Tags("health"),
+		core.ResponseBody(http.StatusOK, health.Report{}),
+		core.ResponseBody(http.StatusServiceUnavailable, health.Report{}),
+	)
+	probes.Handle("GET", "/readyz", health.ReadinessHandler(a.health, a.isAdmin),
+		core.Summary("就绪检查", "服务关闭期间返回 503；管理员令牌可查看检查明细"),
+		core.Tags("health"),
+		core.ResponseBody(http.StatusOK, health.Report{}),
+		core.ResponseBody(http.StatusServiceUnavailable, health.Report{}),
+	)
+}
+
+// isAdmin 判断请求是否携带有效的管理员令牌
+func (a *App) isAdmin(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return false
+	}
+	claims, err := a.jwt.ParseToken(header)
+	return err == nil && claims.Role == "admin"
+}
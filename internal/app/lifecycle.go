@@ -0,0 +1,108 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/lifecycle"
+
+	"go.uber.org/zap"
+)
+
+// 生命周期钩子的顺序，越小越先启动、越后停止
+// 关闭时先让就绪检查失败并等待负载均衡摘除实例，再停止接受连接并等待正在处理的请求，
+// 然后等待后台任务与长连接结束，最后关闭外部资源
+const (
+	orderResource  = 0
+	orderTasks     = 10
+	orderServer    = 20
+	orderReadiness = 30
+)
+
+// initLifecycle 注册各组件的启动与停止钩子
+func (a *App) initLifecycle() {
+	a.tasks = lifecycle.NewTasks()
+	a.serveErrs = make(chan error, 2)
+	a.listeners = make(map[string]net.Listener)
+	a.lifecycle = lifecycle.New(a.logger)
+
+	// 相同顺序的钩子逆序停止，数据库最后关闭
+	a.lifecycle.Append(
+		lifecycle.Hook{Name: "database", Order: orderResource, OnStop: func(context.Context) error {
+			return a.db.Close()
+		}},
+		lifecycle.Hook{Name: "rate-limiter", Order: orderResource, OnStop: a.closeRateLimiter},
+		lifecycle.Hook{Name: "error-reporter", Order: orderResource, OnStop: a.flushReporter},
+		lifecycle.Hook{Name: "tracing", Order: orderResource, OnStop: a.shutdownTracing},
+		lifecycle.Hook{Name: "tasks", Order: orderTasks, OnStop: a.tasks.Wait},
+		lifecycle.Hook{Name: "config-watcher", Order: orderTasks, OnStart: a.watchConfig},
+	)
+	// 相同顺序的钩子按添加顺序启动，继承的监听器先于服务器读取
+	a.lifecycle.Append(lifecycle.Hook{Name: "listeners", Order: orderServer, OnStart: a.inheritListeners})
+	if a.admin != nil {
+		a.lifecycle.Append(a.serverHook("admin", a.admin))
+	}
+	a.lifecycle.Append(
+		a.serverHook("api", a.server),
+		lifecycle.Hook{Name: "readiness", Order: orderReadiness, OnStart: a.notifyUpgraded, OnStop: a.drain},
+	)
+}
+
+// serverHook 返回服务器的钩子，启动时使用继承的监听器或同步监听端口，请求在后台处理，处理出错时通知 Run 退出
+func (a *App) serverHook(name string, srv *core.Server) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name:  name + "-server",
+		Order: orderServer,
+		OnStart: func(context.Context) error {
+			ln, ok := a.inherited[name]
+			if !ok {
+				var err error
+				if ln, err = srv.Listen(); err != nil {
+					return err
+				}
+			}
+			a.listeners[name] = ln
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					a.serveErrs <- fmt.Errorf("%s server stopped: %w", name, err)
+				}
+			}()
+			return nil
+		},
+		OnStop: srv.Stop,
+	}
+}
+
+// drain 让就绪检查立即失败，并在配置的时间内继续处理请求，等待负载均衡停止转发新请求
+// 监听器已交给升级后的新进程时跳过：两个进程共享端口，就绪检查失败会使负载均衡摘除新进程
+func (a *App) drain(ctx context.Context) error {
+	if a.upgraded {
+		a.logger.Info("Listeners handed over, skipping readiness drain")
+		return nil
+	}
+	a.health.MarkShuttingDown()
+	delay := a.config.Server.DrainDelay
+	if delay <= 0 {
+		return nil
+	}
+
+	a.logger.Info("Readiness failing, draining traffic", zap.Duration("delay", delay))
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Tasks 返回后台任务跟踪器，后台任务与 WebSocket 等长连接应在此登记，关闭时等待它们结束
+func (a *App) Tasks() *lifecycle.Tasks {
+	return a.tasks
+}
@@ -0,0 +1,13 @@
+package app
+
+import "go-api-mono/internal/pkg/http/middleware"
+
+// loggerOptions 返回访问日志中间件选项
+func (a *App) loggerOptions() middleware.LoggerOptions {
+	access := a.config.Log.Access
+	return middleware.LoggerOptions{
+		Format:        access.Format,
+		SlowThreshold: access.SlowThreshold,
+		SampleRate:    access.SampleRate,
+	}
+}
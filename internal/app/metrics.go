@@ -0,0 +1,60 @@
+package app
+
+import (
+	"net/http"
+
+	"go-api-mono/internal/pkg/config"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/http/middleware"
+	"go-api-mono/internal/pkg/logger"
+)
+
+// newAdminServer 创建管理服务器，仅在启用指标并配置了独立端口时创建，否则返回nil
+// 未设置空闲超时，空闲连接沿用读超时
+func newAdminServer(cfg *config.Config, log *logger.Logger) *core.Server {
+	if !cfg.Metrics.Enabled || cfg.Metrics.Port == 0 {
+		return nil
+	}
+	return core.NewServer(core.ServerOptions{
+		Port:         cfg.Metrics.Port,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		Logger:       log,
+	})
+}
+
+// registerMetricsRoutes 注册 Prometheus 指标路由
+// 配置了管理端口时注册到管理服务器，否则与业务路由共用端口
+func (a *App) registerMetricsRoutes() {
+	if a.metrics == nil {
+		return
+	}
+
+	srv := a.server
+	if a.admin != nil {
+		srv = a.admin
+	}
+	srv.Group("").Handle("GET", a.config.Metrics.Path, core.Adapt(a.metrics.Handler().ServeHTTP),
+		core.Hidden(),
+		core.HandlerName("metrics.Handler"),
+	)
+}
+
+// metricsMiddleware 返回指标中间件，未启用指标时返回空中间件
+func (a *App) metricsMiddleware() middleware.Middleware {
+	if a.metrics == nil {
+		return middleware.Noop
+	}
+	return middleware.Metrics(a.metrics)
+}
+
+// recoveryOptions 返回恢复中间件选项，恢复的 panic 交给错误上报器并计入指标
+func (a *App) recoveryOptions() middleware.RecoveryOptions {
+	opts := middleware.RecoveryOptions{Reporter: a.reporter}
+	if a.metrics != nil {
+		opts.OnPanic = func(r *http.Request, _ interface{}) {
+			a.metrics.PanicRecovered(r)
+		}
+	}
+	return opts
+}
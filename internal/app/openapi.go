@@ -0,0 +1,109 @@
+package app
+
+import (
+	"fmt"
+
+	"go-api-mono/internal/pkg/auth"
+	"go-api-mono/internal/pkg/config"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/health"
+	"go-api-mono/internal/pkg/idempotency"
+	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/metrics"
+	"go-api-mono/internal/pkg/openapi"
+	"go-api-mono/internal/pkg/security"
+)
+
+// bearerAuth JWT认证的安全方案名称
+const bearerAuth = "BearerAuth"
+
+// docsAssetsPath Swagger UI 静态资源的路径
+const docsAssetsPath = "/docs/assets"
+
+// registerDocRoutes 注册 OpenAPI 文档与 Swagger UI 路由
+func (a *App) registerDocRoutes() error {
+	spec, err := openapi.Handler(a.openAPIDocument())
+	if err != nil {
+		return fmt.Errorf("failed to build openapi document: %w", err)
+	}
+
+	docs := a.server.Group("")
+	a.useCORS(docs)
+	docs.Handle("GET", "/openapi.json", core.Adapt(spec.ServeHTTP), core.Hidden(), core.HandlerName("openapi.Handler"))
+	docs.Handle("GET", "/docs", core.Adapt(openapi.UIHandler(a.config.App.Name, "/openapi.json", docsAssetsPath, cspNonce).ServeHTTP),
+		core.Hidden(),
+		core.HandlerName("openapi.UIHandler"),
+		withSecureHeaders(docsSecureHeaders),
+	)
+	docs.Handle("GET", docsAssetsPath+"/{file}", core.Adapt(openapi.UIAssetsHandler(docsAssetsPath+"/").ServeHTTP),
+		core.Hidden(),
+		core.HandlerName("openapi.UIAssetsHandler"),
+	)
+	return nil
+}
+
+// openAPIDocument 根据已注册的路由生成 OpenAPI 文档
+func (a *App) openAPIDocument() *openapi.Document {
+	return openapi.Generate(a.server.Routes(), openapi.Options{
+		Info: openapi.Info{
+			Title:       a.config.App.Name,
+			Description: "A modern Go API monolith project using clean architecture",
+			Version:     a.config.App.Version,
+		},
+		Servers: []openapi.Server{
+			{URL: "/", Description: "Current server"},
+		},
+		SecuritySchemes: map[string]*openapi.SecurityScheme{
+			bearerAuth: {
+				Type:         "http",
+				Scheme:       "bearer",
+				BearerFormat: "JWT",
+			},
+		},
+	})
+}
+
+// GenerateOpenAPI 加载配置并生成 OpenAPI 文档
+// 只注册路由而不连接数据库，供文档生成命令使用
+func GenerateOpenAPI() (*openapi.Document, error) {
+	a, err := newOfflineApp()
+	if err != nil {
+		return nil, err
+	}
+	return a.openAPIDocument(), nil
+}
+
+// newOfflineApp 加载配置并创建只注册了路由的应用实例，不连接数据库与外部服务
+func newOfflineApp() (*App, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	log := logger.NewNop()
+	a := &App{
+		config: cfg,
+		logger: log,
+		jwt: auth.New(auth.Config{
+			SigningKey:     cfg.JWT.SigningKey,
+			ExpirationTime: cfg.JWT.ExpirationTime,
+			SigningMethod:  cfg.JWT.SigningMethod,
+			TokenPrefix:    cfg.JWT.TokenPrefix,
+		}),
+		limiter: security.NewMemoryRateLimiter(security.AlgorithmTokenBucket, security.MemoryOptions{}),
+		idem:    idempotency.NewMemoryStore(),
+		health:  health.NewRegistry(cfg.Health.Timeout, cfg.Health.CacheTTL),
+		server:  core.NewServer(core.ServerOptions{Port: cfg.Server.Port, Logger: log}),
+		admin:   newAdminServer(cfg, log),
+		// 与运行时相同的可选组件，使路由表中的中间件与实际一致
+		concurrency: newConcurrencyLimiter(cfg.Concurrency),
+	}
+	if cfg.Metrics.Enabled {
+		a.metrics = metrics.New()
+	}
+
+	if err := a.initRoutes(); err != nil {
+		return nil, fmt.Errorf("failed to initialize routes: %w", err)
+	}
+	return a, nil
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go-api-mono/internal/pkg/config"
+	"go-api-mono/internal/pkg/http/middleware"
+	"go-api-mono/internal/pkg/security"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// newRateLimiter 按配置创建限流后端
+// 使用 Redis 存储时所有副本共享计数，否则每个副本独立限流
+func (a *App) newRateLimiter() (security.RateLimiter, error) {
+	cfg := a.config.RateLimit
+	algorithm, err := security.ParseAlgorithm(cfg.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Store != "redis" {
+		return security.NewMemoryRateLimiter(algorithm, security.MemoryOptions{
+			MaxEntries:      cfg.MaxEntries,
+			IdleTTL:         cfg.IdleTTL,
+			CleanupInterval: cfg.IdleTTL / 10,
+		}), nil
+	}
+	a.redis = redis.NewClient(&redis.Options{
+		Addr:     a.config.Redis.Addr,
+		Password: a.config.Redis.Password,
+		DB:       a.config.Redis.DB,
+	})
+	return security.NewRedisRateLimiter(a.redis, algorithm, cfg.KeyPrefix), nil
+}
+
+// rateLimitRules 将配置中的限流策略与路由绑定转换为中间件规则
+func rateLimitRules(cfg config.RateLimitConfig) ([]middleware.RateLimitPolicy, []middleware.RateLimitRoute) {
+	quota := func(q config.RateLimitQuotaConfig) security.Limit {
+		return security.Limit{Rate: float64(q.Requests), Burst: q.Burst}
+	}
+
+	var policies []middleware.RateLimitPolicy
+	for name, p := range cfg.AllPolicies() {
+		policy := middleware.RateLimitPolicy{
+			Name:  name,
+			Limit: quota(p.RateLimitQuotaConfig),
+			KeyBy: p.KeyBy,
+			Roles: make(map[string]security.Limit, len(p.Roles)),
+		}
+		for role, q := range p.Roles {
+			policy.Roles[role] = quota(q)
+		}
+		policies = append(policies, policy)
+	}
+
+	routes := make([]middleware.RateLimitRoute, 0, len(cfg.Routes))
+	for _, r := range cfg.Routes {
+		routes = append(routes, middleware.RateLimitRoute{Route: r.Route, Policy: r.Policy, Cost: r.Cost})
+	}
+	return policies, routes
+}
+
+// rateLimitMiddleware 返回按路由策略限流的中间件
+func (a *App) rateLimitMiddleware() middleware.Middleware {
+	return middleware.PolicyRateLimit(a.limiter, a.rateLimits, a.rateLimitOptions())
+}
+
+// applyRateLimits 按重新加载的配置替换限流策略与路由绑定
+// 限流算法与存储后端需要重启才能生效
+func (a *App) applyRateLimits(cfg *config.Config) error {
+	if err := a.rateLimits.Update(rateLimitRules(cfg.RateLimit)); err != nil {
+		return fmt.Errorf("failed to update rate limit rules: %w", err)
+	}
+	a.logger.Info("Rate limit policies reloaded",
+		zap.Int("policies", len(cfg.RateLimit.AllPolicies())),
+		zap.Int("routes", len(cfg.RateLimit.Routes)))
+	return nil
+}
+
+// rateLimitOptions 返回限流中间件选项，被拒绝的请求计入指标
+func (a *App) rateLimitOptions() middleware.RateLimitOptions {
+	opts := middleware.DefaultRateLimitOptions
+	if a.metrics != nil {
+		opts.OnLimited = a.metrics.RateLimited
+	}
+	opts.OnError = func(r *http.Request, err error) {
+		a.logger.WithContext(r.Context()).Warn("Rate limiter unavailable, request allowed", zap.Error(err))
+	}
+	return opts
+}
+
+// closeRateLimiter 停止内存限流器的清理协程，关闭 Redis 连接
+func (a *App) closeRateLimiter(context.Context) error {
+	var errs []error
+	if closer, ok := a.limiter.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close rate limiter: %w", err))
+		}
+	}
+	if a.redis != nil {
+		if err := a.redis.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close redis connection: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"go-api-mono/internal/pkg/config"
+
+	"go.uber.org/zap"
+)
+
+// initReload 创建配置存储，各组件订阅可重新加载的配置项
+// 功能开关没有订阅者，每次调用 Feature 时读取当前配置
+func (a *App) initReload() {
+	a.configs = config.NewStore(a.config)
+	a.configs.Subscribe("log level", a.applyLogLevel, "log.level")
+	a.configs.Subscribe("rate limit policies", a.applyRateLimits, "rateLimit")
+	a.configs.Subscribe("body capture targets", a.applyBodyCapture, "bodyCapture")
+	a.configs.Subscribe("cors policies", a.applyCORS, "cors")
+}
+
+// ReloadConfig 重新读取并校验配置，替换日志级别、限流策略、请求体记录目标、跨域策略与功能开关
+// 新配置无效时保留原有配置；端口、数据库等需要重启才能生效的变化记录警告日志
+func (a *App) ReloadConfig() error {
+	changes, err := a.configs.Reload()
+	if len(changes.Ignored) > 0 {
+		a.logger.Warn("Config changes require a restart and were not applied", zap.Strings("fields", changes.Ignored))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reload config: %w", err)
+	}
+	a.logger.Info("Config reloaded", zap.Strings("applied", changes.Applied))
+	return nil
+}
+
+// Feature 判断功能开关是否开启，未配置的功能视为关闭
+func (a *App) Feature(name string) bool {
+	return a.configs.Current().Features[name]
+}
+
+// applyLogLevel 按重新加载的配置调整日志级别
+func (a *App) applyLogLevel(cfg *config.Config) error {
+	if err := a.logger.SetLevel(cfg.Log.Level); err != nil {
+		return err
+	}
+	a.logger.Info("Log level changed", zap.String("level", cfg.Log.Level))
+	return nil
+}
+
+// applyCORS 按重新加载的配置更新已注册路由组的跨域策略
+func (a *App) applyCORS(cfg *config.Config) error {
+	if err := a.cors.update(cfg.CORS); err != nil {
+		return err
+	}
+	a.logger.Info("CORS policies reloaded", zap.Int("overrides", len(cfg.CORS.Overrides)))
+	return nil
+}
+
+// watchConfig 开启了 reload.watch 且通过 CONFIG_FILE 指定了配置文件时，在后台监视配置文件的变化
+// 嵌入的配置文件在运行期间不会变化，只能通过 SIGHUP 重新加载
+func (a *App) watchConfig(context.Context) error {
+	path := config.FilePath()
+	if !a.config.Reload.Watch || path == "" {
+		return nil
+	}
+
+	a.logger.Info("Watching config file", zap.String("path", path), zap.Duration("interval", a.config.Reload.Interval))
+	return a.tasks.Go("config-watcher", func(ctx context.Context) {
+		config.Watch(ctx, path, a.config.Reload.Interval, func() {
+			a.logger.Info("Config file changed, reloading", zap.String("path", path))
+			if err := a.ReloadConfig(); err != nil {
+				a.logger.Error("Failed to reload config", zap.Error(err))
+			}
+		})
+	})
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go-api-mono/internal/pkg/reporting"
+)
+
+// newReporter 创建错误上报器，恢复的 panic 始终记录日志，配置了 Sentry DSN 时同时上报到 Sentry
+func (a *App) newReporter() (reporting.Reporter, error) {
+	logReporter := reporting.NewLogReporter(a.logger)
+	cfg := a.config.ErrorReporting
+	if cfg.SentryDSN == "" {
+		return logReporter, nil
+	}
+
+	hostname, _ := os.Hostname()
+	sentry, err := reporting.NewSentryReporter(reporting.SentryOptions{
+		DSN:         cfg.SentryDSN,
+		Environment: a.config.App.Mode,
+		Release:     a.config.App.Version,
+		ServerName:  hostname,
+		Timeout:     cfg.Timeout,
+		Logger:      a.logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.sentry = sentry
+	return reporting.Multi(logReporter, sentry), nil
+}
+
+// flushReporter 等待尚未发送完成的错误事件
+func (a *App) flushReporter(ctx context.Context) error {
+	if a.sentry == nil {
+		return nil
+	}
+	if err := a.sentry.Flush(ctx); err != nil {
+		return fmt.Errorf("failed to flush error reports: %w", err)
+	}
+	return nil
+}
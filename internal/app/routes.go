@@ -0,0 +1,248 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+
+	apispec "go-api-mono/api"
+	"go-api-mono/internal/app/user/controller"
+	"go-api-mono/internal/app/user/model"
+	"go-api-mono/internal/app/user/repository"
+	"go-api-mono/internal/app/user/service"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/http/middleware"
+	"go-api-mono/internal/pkg/openapi"
+)
+
+// authMaxBodySize 认证接口的请求体大小上限
+const authMaxBodySize = 16 << 10
+
+// userHandlers 某个API版本下用户资源的处理函数
+type userHandlers struct {
+	Register core.HandlerFunc
+	Login    core.HandlerFunc
+	Logout   core.HandlerFunc
+	List     core.HandlerFunc
+	Get      core.HandlerFunc
+	Update   core.HandlerFunc
+	Delete   core.HandlerFunc
+
+	// 各版本之间存在差异的文档信息
+	RegisterStatus int         // 注册成功的状态码
+	ListResponse   interface{} // 用户列表的响应类型
+}
+
+// initRoutes 初始化路由
+func (a *App) initRoutes() error {
+	// 编译跨域策略
+	cors, err := newCORSPolicies(a.config.CORS)
+	if err != nil {
+		return fmt.Errorf("failed to build cors policies: %w", err)
+	}
+	a.cors = cors
+
+	// 创建会话管理器，按客户端类型选择 Bearer 令牌或 Cookie 会话
+	a.sessions = a.newSessions()
+
+	// 编译限流策略
+	rules, err := middleware.NewRateLimitRules(rateLimitRules(a.config.RateLimit))
+	if err != nil {
+		return fmt.Errorf("failed to build rate limit rules: %w", err)
+	}
+	a.rateLimits = rules
+
+	// 请求体记录目标
+	a.bodyCapture = middleware.NewBodyCaptureRules(bodyCaptureTargets(a.config.BodyCapture))
+
+	// 加载嵌入的 OpenAPI 文档用于请求校验
+	if a.config.Validation.Requests {
+		doc, err := openapi.Load(apispec.GetSpec())
+		if err != nil {
+			return fmt.Errorf("failed to load api specification: %w", err)
+		}
+		a.validator = openapi.NewValidator(doc)
+	}
+
+	// 创建用户仓储
+	userRepo := repository.NewUserRepository(a.db)
+
+	// 创建用户服务，各版本的控制器共享同一服务
+	userService := service.NewUserService(userRepo)
+
+	// 创建各版本的用户控制器
+	userV1 := controller.NewUserController(userService, a.jwt, a.sessions)
+	userV2 := controller.NewUserControllerV2(userService, a.jwt, a.sessions)
+
+	// 全局中间件，指标位于最外层以统计所有响应，链路追踪与请求ID先于日志与恢复，
+	// 使错误响应与日志携带 trace_id 与 request_id；访问日志位于恢复之外以记录 panic 产生的 500，
+	// 并发限制位于日志之后，使被拒绝的请求同样记录日志；请求体记录位于最内层，只记录实际处理的请求
+	a.server.UseNamed(middleware.Named(
+		a.metricsMiddleware(),
+		middleware.Tracing(),
+		middleware.RequestID(),
+		middleware.SecureHeaders(a.secureHeaderOptions()),
+		middleware.LoggerWithOptions(a.logger, a.loggerOptions()),
+		middleware.RecoveryWithOptions(a.logger, a.recoveryOptions()),
+		a.concurrencyMiddleware(),
+		a.bodyCaptureMiddleware(),
+	)...)
+
+	// 版本化API：/api/v1、/api/v2，或 /api 配合 API-Version 请求头 / Accept 参数
+	api := a.server.API("/api", core.VersioningOptions{
+		Default:   a.config.API.DefaultVersion,
+		MediaType: "application/vnd.go-api-mono+json",
+	})
+
+	a.registerUserRoutes(api.Version("v1", a.versionOptions("v1")...), userHandlers{
+		Register: userV1.Register,
+		Login:    userV1.Login,
+		Logout:   userV1.Logout,
+		List:     userV1.List,
+		Get:      userV1.Get,
+		Update:   userV1.Update,
+		Delete:   userV1.Delete,
+
+		RegisterStatus: http.StatusOK,
+		ListResponse:   model.UserPage{},
+	})
+	a.registerUserRoutes(api.Version("v2", a.versionOptions("v2")...), userHandlers{
+		Register: userV2.Register,
+		Login:    userV2.Login,
+		Logout:   userV2.Logout,
+		List:     userV2.List,
+		Get:      userV2.Get,
+		Update:   userV2.Update,
+		Delete:   userV2.Delete,
+
+		RegisterStatus: http.StatusCreated,
+		ListResponse:   model.UserListResponse{},
+	})
+
+	// 健康检查
+	a.registerHealthRoutes()
+
+	// 监控指标
+	a.registerMetricsRoutes()
+
+	// 路由表
+	a.registerRouteTableRoutes()
+
+	// API文档，需在业务路由注册完成后生成
+	if err := a.registerDocRoutes(); err != nil {
+		return err
+	}
+
+	// 重复或无法区分的路由注册导致启动失败
+	return a.checkRoutes()
+}
+
+// registerUserRoutes 在指定版本的路由组下注册用户路由
+func (a *App) registerUserRoutes(v *core.Group, h userHandlers) {
+	// 路由级选项：请求体大小上限、处理器超时与按文档校验请求
+	routeOpts := core.WithNamedMiddleware(middleware.Named(
+		middleware.Timeout(a.config.Server.HandlerTimeout),
+		middleware.BodyLimit(a.config.Server.MaxBodySize),
+		a.validation(),
+	)...)
+	// 认证接口的请求体很小，使用更严格的上限
+	authRouteOpts := core.WithNamedMiddleware(middleware.Named(
+		middleware.Timeout(a.config.Server.HandlerTimeout),
+		middleware.BodyLimit(authMaxBodySize),
+		a.validation(),
+	)...)
+
+	// 幂等中间件，用于客户端可能重试的写操作
+	idemOpts := middleware.DefaultIdempotencyOptions
+	idemOpts.TTL = a.config.Idempotency.TTL
+	idem := core.WithNamedMiddleware(middleware.Named(middleware.Idempotency(a.idem, idemOpts))...)
+
+	// 公开路由
+	public := v.Group("/auth")
+	a.useCORS(public)
+	public.UseNamed(middleware.Named(
+		a.apiKeyMiddleware(),
+		a.rateLimitMiddleware(),
+	)...)
+	public.Handle("POST", "/login", h.Login, authRouteOpts,
+		core.Summary("用户登录", "X-Client-Type 请求头对应 Cookie 会话模式时，令牌写入 HttpOnly Cookie，响应返回 CSRF 令牌"),
+		core.Tags("auth"),
+		core.HeaderParam(a.sessions.Config().ClientHeader, "string", "客户端类型，如 web、mobile"),
+		core.RequestBody(model.LoginRequest{}),
+		core.ResponseBody(http.StatusOK, model.LoginResponse{}),
+	)
+	public.Handle("POST", "/logout", h.Logout, authRouteOpts,
+		core.Summary("退出登录", "清除 Cookie 会话"),
+		core.Tags("auth"),
+		core.ResponseBody(http.StatusNoContent, nil),
+	)
+	public.Handle("POST", "/register", h.Register, authRouteOpts, idem,
+		core.Summary("用户注册", "支持 Idempotency-Key 请求头，重复请求将重放首次响应"),
+		core.Tags("auth"),
+		core.HeaderParam(idemOpts.HeaderName, "string", "幂等键"),
+		core.RequestBody(model.RegisterRequest{}),
+		core.ResponseBody(h.RegisterStatus, model.RegisterResponse{}),
+	)
+
+	// 需要认证的路由
+	protected := v.Group("/users")
+	a.useCORS(protected)
+	protected.UseNamed(middleware.Named(
+		middleware.JWT(a.jwt, a.jwtOptions()),
+		a.apiKeyMiddleware(),
+		a.rateLimitMiddleware(),
+	)...)
+	protected.Handle("GET", "", h.List, routeOpts,
+		core.Summary("获取用户列表"),
+		core.Tags("users"),
+		core.Security(bearerAuth),
+		core.QueryParam("page", "integer", "页码，从1开始"),
+		core.QueryParam("page_size", "integer", "每页数量，最大100"),
+		core.ResponseBody(http.StatusOK, h.ListResponse),
+	)
+	protected.Handle("GET", "/{id}", h.Get, routeOpts,
+		core.Summary("获取用户详情"),
+		core.Tags("users"),
+		core.Security(bearerAuth),
+		core.PathParam("id", "integer", "用户ID"),
+		core.ResponseBody(http.StatusOK, model.User{}),
+	)
+	protected.Handle("PUT", "/{id}", h.Update, routeOpts,
+		core.Summary("更新用户信息"),
+		core.Tags("users"),
+		core.Security(bearerAuth),
+		core.PathParam("id", "integer", "用户ID"),
+		core.RequestBody(model.User{}),
+		core.ResponseBody(http.StatusOK, model.User{}),
+	)
+	protected.Handle("DELETE", "/{id}", h.Delete, routeOpts,
+		core.Summary("删除用户"),
+		core.Tags("users"),
+		core.Security(bearerAuth),
+		core.PathParam("id", "integer", "用户ID"),
+		core.ResponseBody(http.StatusNoContent, nil),
+	)
+}
+
+// validation 返回请求校验中间件，未启用时返回空中间件
+func (a *App) validation() middleware.Middleware {
+	if a.validator == nil {
+		return middleware.Noop
+	}
+	return middleware.Validation(a.validator, middleware.ValidationOptions{
+		ValidateResponses: a.config.Validation.Responses,
+		Logger:            a.logger,
+	})
+}
+
+// versionOptions 根据配置返回版本选项
+func (a *App) versionOptions(version string) []core.VersionOption {
+	d, ok := a.config.API.Deprecations[version]
+	if !ok {
+		return nil
+	}
+	return []core.VersionOption{core.Deprecated(core.Deprecation{
+		Since:  d.Since,
+		Sunset: d.Sunset,
+		Link:   d.Link,
+	})}
+}
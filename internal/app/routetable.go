@@ -0,0 +1,101 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/errors"
+)
+
+// rateLimitMiddlewareName 路由表中按路由策略限流的中间件名称
+const rateLimitMiddlewareName = "middleware.PolicyRateLimit"
+
+// RouteInfo 路由表中的一条路由
+type RouteInfo struct {
+	Server     string   `json:"server"`               // 所在服务器：api 或 admin
+	Method     string   `json:"method"`               // 请求方法
+	Path       string   `json:"path"`                 // 路径模式
+	Handler    string   `json:"handler"`              // 处理器名称
+	Middleware []string `json:"middleware"`           // 中间件，由外到内
+	Security   []string `json:"security,omitempty"`   // 需要的认证方案
+	RateLimit  string   `json:"rate_limit,omitempty"` // 限流策略，未经过限流中间件时为空
+}
+
+// routeTable 返回所有服务器上注册的路由，不包括自动注册的 OPTIONS 路由
+func (a *App) routeTable() []RouteInfo {
+	table := a.serverRoutes("api", a.server)
+	if a.admin != nil {
+		table = append(table, a.serverRoutes("admin", a.admin)...)
+	}
+	return table
+}
+
+// serverRoutes 返回单个服务器上注册的路由
+func (a *App) serverRoutes(name string, srv *core.Server) []RouteInfo {
+	var table []RouteInfo
+	for _, route := range srv.Routes() {
+		if route.Automatic {
+			continue
+		}
+		info := RouteInfo{
+			Server:     name,
+			Method:     route.Method,
+			Path:       route.Path,
+			Handler:    route.Handler,
+			Middleware: route.Stack,
+			Security:   route.Security,
+		}
+		if slices.Contains(route.Stack, rateLimitMiddlewareName) {
+			info.RateLimit = a.rateLimits.Policy(route)
+		}
+		table = append(table, info)
+	}
+	return table
+}
+
+// registerRouteTableRoutes 注册路由表接口
+// 配置了管理端口时注册到管理服务器，否则与业务路由共用端口并要求管理员令牌
+func (a *App) registerRouteTableRoutes() {
+	if a.admin != nil {
+		a.admin.Group("").Handle("GET", "/debug/routes", a.listRoutes, core.Hidden())
+		return
+	}
+	a.server.Group("").Handle("GET", "/debug/routes", a.listRoutesAsAdmin, core.Hidden())
+}
+
+// listRoutes 返回路由表
+func (a *App) listRoutes(c *core.Context) {
+	c.Response.Success(a.routeTable())
+}
+
+// listRoutesAsAdmin 校验管理员令牌后返回路由表
+func (a *App) listRoutesAsAdmin(c *core.Context) {
+	if !a.isAdmin(c.Request) {
+		c.Response.Error(errors.ErrForbidden)
+		return
+	}
+	a.listRoutes(c)
+}
+
+// checkRoutes 检查注册路由时发现的冲突
+func (a *App) checkRoutes() error {
+	if err := a.server.Err(); err != nil {
+		return fmt.Errorf("conflicting routes: %w", err)
+	}
+	if a.admin != nil {
+		if err := a.admin.Err(); err != nil {
+			return fmt.Errorf("conflicting admin routes: %w", err)
+		}
+	}
+	return nil
+}
+
+// RouteTable 加载配置并返回路由表，不连接数据库，供 routes 命令使用
+func RouteTable() ([]RouteInfo, error) {
+	a, err := newOfflineApp()
+	if err != nil {
+		return nil, err
+	}
+	return a.routeTable(), nil
+}
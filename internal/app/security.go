@@ -0,0 +1,88 @@
+package app
+
+import (
+	"net/http"
+
+	"go-api-mono/internal/pkg/auth"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/http/middleware"
+)
+
+// docsSecureHeaders Swagger UI 页面的安全响应头覆盖
+// 页面只加载本服务内置的脚本与样式，脚本通过 nonce 放行；Swagger UI 使用内联样式
+var docsSecureHeaders = middleware.SecureHeadersOptions{
+	ContentSecurityPolicy: "default-src 'none'; " +
+		"script-src 'nonce-{nonce}'; " +
+		"style-src 'self' 'unsafe-inline'; " +
+		"img-src 'self' data:; " +
+		"connect-src 'self'; " +
+		"frame-ancestors 'none'; base-uri 'none'; form-action 'none'",
+}
+
+// secureHeaderOptions 返回运行模式的默认安全响应头与配置合并后的选项
+func (a *App) secureHeaderOptions() middleware.SecureHeadersOptions {
+	cfg := a.config.SecurityHeaders
+	return middleware.DefaultSecureHeadersOptions(a.config.App.Mode).Merge(middleware.SecureHeadersOptions{
+		StrictTransportSecurity:   cfg.StrictTransportSecurity,
+		ContentSecurityPolicy:     cfg.ContentSecurityPolicy,
+		ContentTypeOptions:        cfg.ContentTypeOptions,
+		FrameOptions:              cfg.FrameOptions,
+		ReferrerPolicy:            cfg.ReferrerPolicy,
+		PermissionsPolicy:         cfg.PermissionsPolicy,
+		CrossOriginOpenerPolicy:   cfg.CrossOriginOpenerPolicy,
+		CrossOriginResourcePolicy: cfg.CrossOriginResourcePolicy,
+		CrossOriginEmbedderPolicy: cfg.CrossOriginEmbedderPolicy,
+	})
+}
+
+// withSecureHeaders 返回覆盖安全响应头的路由选项
+func withSecureHeaders(opts middleware.SecureHeadersOptions) core.RouteOption {
+	return core.WithNamedMiddleware(middleware.Named(middleware.SecureHeaders(opts))...)
+}
+
+// cspNonce 返回当前请求 CSP 中的 nonce
+func cspNonce(r *http.Request) string {
+	return middleware.CSPNonce(r.Context())
+}
+
+// newSessions 根据配置创建会话管理器
+func (a *App) newSessions() *auth.Sessions {
+	cfg := a.config.Session
+	sameSite := http.SameSiteLaxMode
+	switch cfg.Cookie.SameSite {
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	}
+
+	return auth.NewSessions(auth.SessionConfig{
+		DefaultMode:    cfg.DefaultMode,
+		ClientHeader:   cfg.ClientHeader,
+		Clients:        cfg.Clients,
+		CookieName:     cfg.Cookie.Name,
+		CookieDomain:   cfg.Cookie.Domain,
+		CookiePath:     cfg.Cookie.Path,
+		Secure:         cfg.Cookie.Secure,
+		SameSite:       sameSite,
+		CSRFCookieName: cfg.CSRF.CookieName,
+		CSRFHeaderName: cfg.CSRF.HeaderName,
+	})
+}
+
+// apiKeyMiddleware 返回 API Key 认证中间件，识别出的 API Key 用于按 API Key 限流
+func (a *App) apiKeyMiddleware() middleware.Middleware {
+	opts := middleware.DefaultAPIKeyOptions
+	opts.Header = a.config.APIKey.Header
+	return middleware.APIKey(a.config.APIKey.Keys, opts)
+}
+
+// jwtOptions 返回JWT中间件选项，同时接受 Authorization 请求头与会话 Cookie
+func (a *App) jwtOptions() middleware.JWTOptions {
+	session := a.sessions.Config()
+	opts := middleware.DefaultJWTOptions
+	opts.CookieName = session.CookieName
+	opts.CSRFHeaderName = session.CSRFHeaderName
+	opts.TrustedOrigins = a.config.Session.CSRF.TrustedOrigins
+	return opts
+}
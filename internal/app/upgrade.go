@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-api-mono/internal/pkg/health"
+	"go-api-mono/internal/pkg/listener"
+
+	"go.uber.org/zap"
+)
+
+// inheritListeners 读取 systemd socket activation 或升级前的进程传递的监听器
+// 未命名的监听器依次作为业务端口与管理端口，没有对应服务器的监听器会被关闭
+func (a *App) inheritListeners(context.Context) error {
+	inherited, err := listener.Inherit("api", "admin")
+	if err != nil {
+		return err
+	}
+	for name, ln := range inherited {
+		if name == "api" || (name == "admin" && a.admin != nil) {
+			a.logger.Info("Using inherited listener", zap.String("listener", name), zap.String("addr", ln.Addr().String()))
+			continue
+		}
+		a.logger.Warn("Closing unused inherited listener", zap.String("listener", name), zap.String("addr", ln.Addr().String()))
+		ln.Close()
+		delete(inherited, name)
+	}
+	a.inherited = inherited
+	return nil
+}
+
+// notifyUpgraded 由升级启动时，在就绪检查通过后通知旧进程退出
+// 就绪检查失败时启动失败，旧进程继续提供服务
+func (a *App) notifyUpgraded(ctx context.Context) error {
+	if !listener.Upgrading() {
+		return nil
+	}
+	if report := a.health.Readiness(ctx); report.Status != health.StatusUp {
+		return errors.New("readiness check failed after upgrade")
+	}
+	return listener.Ready()
+}
+
+// upgrade 启动新的可执行文件并传递监听器，新进程就绪后当前进程进入优雅关闭
+func (a *App) upgrade() error {
+	a.logger.Info("Upgrading, starting new process")
+	begin := time.Now()
+
+	opts := listener.DefaultUpgradeOptions
+	opts.Listeners = a.listeners
+	opts.ReadyTimeout = a.config.Server.UpgradeTimeout
+	proc, err := listener.Upgrade(opts)
+	if err != nil {
+		return err
+	}
+
+	a.upgraded = true
+	a.logger.Info("New process ready, handing over",
+		zap.Int("pid", proc.Pid),
+		zap.Duration("duration", time.Since(begin)))
+	return proc.Release()
+}
@@ -9,19 +9,24 @@ import (
 	"go-api-mono/internal/pkg/auth"
 	"go-api-mono/internal/pkg/core"
 	"go-api-mono/internal/pkg/errors"
+
+	"go.uber.org/zap"
 )
 
 // UserController 用户控制器
 type UserController struct {
-	service *service.UserService
-	jwt     *auth.JWT
+	service  *service.UserService
+	jwt      *auth.JWT
+	sessions *auth.Sessions
 }
 
 // NewUserController 创建用户控制器
-func NewUserController(service *service.UserService, jwt *auth.JWT) *UserController {
+// sessions 为 nil 时登录只返回 Bearer 令牌
+func NewUserController(service *service.UserService, jwt *auth.JWT, sessions *auth.Sessions) *UserController {
 	return &UserController{
-		service: service,
-		jwt:     jwt,
+		service:  service,
+		jwt:      jwt,
+		sessions: sessions,
 	}
 }
 
@@ -57,9 +62,29 @@ func (c *UserController) Login(ctx *core.Context) {
 
 	user, err := c.service.AuthenticateUser(ctx, req.Email, req.Password)
 	if err != nil {
+		audit(ctx, "login_failed", zap.String("email", req.Email))
 		ctx.Response.Error(err)
 		return
 	}
+	audit(ctx, "login_succeeded", zap.Uint("user_id", user.ID))
+
+	// 浏览器客户端使用 Cookie 会话，令牌不暴露给前端脚本
+	if c.sessions != nil && c.sessions.Mode(ctx.Request) == auth.ModeCookie {
+		token, csrf, err := c.jwt.GenerateSessionToken(user.ID, user.Username, "user")
+		if err != nil {
+			ctx.Response.Error(errors.New(errors.ErrCodeInternal, "failed to generate token"))
+			return
+		}
+		c.sessions.SetCookies(ctx.Response.Writer, token, csrf, c.jwt.Config.ExpirationTime)
+
+		ctx.Response.Success(model.LoginResponse{
+			TokenType: "Cookie",
+			ExpiresIn: int64(c.jwt.Config.ExpirationTime.Seconds()),
+			CSRFToken: csrf,
+			User:      user,
+		})
+		return
+	}
 
 	token, err := c.jwt.GenerateToken(user.ID, user.Username, "user")
 	if err != nil {
@@ -75,6 +100,16 @@ func (c *UserController) Login(ctx *core.Context) {
 	})
 }
 
+// Logout 退出登录，清除 Cookie 会话
+// Bearer 令牌由客户端自行丢弃
+func (c *UserController) Logout(ctx *core.Context) {
+	audit(ctx, "logout")
+	if c.sessions != nil {
+		c.sessions.ClearCookies(ctx.Response.Writer)
+	}
+	ctx.Response.NoContent()
+}
+
 // List 获取用户列表
 func (c *UserController) List(ctx *core.Context) {
 	page, _ := strconv.Atoi(ctx.Request.URL.Query().Get("page"))
@@ -96,11 +131,11 @@ func (c *UserController) List(ctx *core.Context) {
 		return
 	}
 
-	ctx.Response.Success(map[string]interface{}{
-		"users": users,
-		"total": total,
-		"page":  page,
-		"size":  pageSize,
+	ctx.Response.Success(model.UserPage{
+		Users: users,
+		Total: total,
+		Page:  page,
+		Size:  pageSize,
 	})
 }
 
@@ -159,3 +194,12 @@ func (c *UserController) Delete(ctx *core.Context) {
 
 	ctx.Response.NoContent()
 }
+
+// audit 记录认证相关的审计事件，客户端IP经受信任代理解析
+func audit(ctx *core.Context, event string, fields ...zap.Field) {
+	if ctx.Logger == nil {
+		return
+	}
+	fields = append([]zap.Field{zap.String("event", event), zap.String("client_ip", ctx.ClientIP())}, fields...)
+	ctx.Logger.WithContext(ctx.Request.Context()).Info("audit", fields...)
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // MockUserRepository 模拟用户仓储
@@ -77,7 +78,9 @@ func setupTest(t *testing.T) (*UserController, *MockUserRepository, *auth.JWT, *
 	})
 
 	userService := service.NewUserService(mockRepo)
-	controller := NewUserController(userService, jwt)
+	controller := NewUserController(userService, jwt, auth.NewSessions(auth.SessionConfig{
+		Clients: map[string]string{"web": auth.ModeCookie},
+	}))
 	return controller, mockRepo, jwt, log
 }
 
@@ -171,4 +174,70 @@ func TestRegister(t *testing.T) {
 	}
 }
 
+func TestLogin(t *testing.T) {
+	controller, mockRepo, jwt, log := setupTest(t)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	user := &model.User{ID: 1, Username: "testuser", Email: "test@example.com", Password: string(hashed)}
+	mockRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(user, nil)
+
+	tests := []struct {
+		name       string
+		clientType string
+		wantCookie bool
+	}{
+		{name: "默认返回Bearer令牌", clientType: "", wantCookie: false},
+		{name: "移动端返回Bearer令牌", clientType: "mobile", wantCookie: false},
+		{name: "浏览器使用Cookie会话", clientType: "web", wantCookie: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(model.LoginRequest{Email: "test@example.com", Password: "password123"})
+			req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+			if tt.clientType != "" {
+				req.Header.Set("X-Client-Type", tt.clientType)
+			}
+			w := httptest.NewRecorder()
+
+			controller.Login(core.NewContext(req, w, log))
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var resp struct {
+				Data model.LoginResponse `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			cookies := map[string]*http.Cookie{}
+			for _, c := range w.Result().Cookies() {
+				cookies[c.Name] = c
+			}
+
+			if !tt.wantCookie {
+				assert.NotEmpty(t, resp.Data.Token)
+				assert.Equal(t, "Bearer", resp.Data.TokenType)
+				assert.Empty(t, cookies)
+				return
+			}
+
+			// 令牌只存在于 HttpOnly Cookie 中，CSRF 令牌与签名令牌绑定
+			assert.Empty(t, resp.Data.Token)
+			assert.NotEmpty(t, resp.Data.CSRFToken)
+			session := cookies["session"]
+			if assert.NotNil(t, session) {
+				assert.True(t, session.HttpOnly)
+				assert.Equal(t, http.SameSiteLaxMode, session.SameSite)
+				claims, err := jwt.ParseToken(session.Value)
+				assert.NoError(t, err)
+				assert.Equal(t, resp.Data.CSRFToken, claims.CSRF)
+			}
+			if assert.NotNil(t, cookies["csrf_token"]) {
+				assert.False(t, cookies["csrf_token"].HttpOnly)
+				assert.Equal(t, resp.Data.CSRFToken, cookies["csrf_token"].Value)
+			}
+		})
+	}
+}
+
 // Add more test functions for other controller methods...
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"go-api-mono/internal/app/user/model"
+	"go-api-mono/internal/app/user/service"
+	"go-api-mono/internal/pkg/auth"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/errors"
+)
+
+// UserControllerV2 v2 版本的用户控制器
+// 与 v1 共享用户服务，未改变的接口直接复用 v1 的实现
+type UserControllerV2 struct {
+	*UserController
+}
+
+// NewUserControllerV2 创建 v2 版本的用户控制器
+func NewUserControllerV2(service *service.UserService, jwt *auth.JWT, sessions *auth.Sessions) *UserControllerV2 {
+	return &UserControllerV2{
+		UserController: NewUserController(service, jwt, sessions),
+	}
+}
+
+// Register 注册用户，v2 返回 201 Created
+func (c *UserControllerV2) Register(ctx *core.Context) {
+	var req model.RegisterRequest
+	if err := json.NewDecoder(ctx.Request.Body).Decode(&req); err != nil {
+		ctx.Response.Error(errors.New(errors.ErrCodeBadRequest, "invalid request body"))
+		return
+	}
+
+	user := &model.User{
+		Username: req.Username,
+		Email:    req.Email,
+		Password: req.Password,
+	}
+
+	if err := c.service.RegisterUser(ctx, user); err != nil {
+		ctx.Response.Error(err)
+		return
+	}
+
+	ctx.Response.Created(model.RegisterResponse{User: user})
+}
+
+// List 获取用户列表，v2 使用统一的分页结构
+func (c *UserControllerV2) List(ctx *core.Context) {
+	page, _ := strconv.Atoi(ctx.Request.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(ctx.Request.URL.Query().Get("page_size"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	users, total, err := c.service.ListUsers(ctx, page, pageSize)
+	if err != nil {
+		ctx.Response.Error(err)
+		return
+	}
+
+	ctx.Response.Success(model.UserListResponse{
+		Items: users,
+		Pagination: model.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	})
+}
@@ -7,10 +7,12 @@ type LoginRequest struct {
 }
 
 // LoginResponse 登录响应
+// Cookie 会话模式下令牌写入 HttpOnly Cookie，响应体不包含 token，而是返回 csrf_token
 type LoginResponse struct {
-	Token     string `json:"token"`
+	Token     string `json:"token,omitempty"`
 	TokenType string `json:"token_type"`
-	ExpiresIn int64  `json:"expires_in"` // 过期时间（秒）
+	ExpiresIn int64  `json:"expires_in"`           // 过期时间（秒）
+	CSRFToken string `json:"csrf_token,omitempty"` // 写请求需通过 X-CSRF-Token 请求头携带
 	User      *User  `json:"user"`
 }
 
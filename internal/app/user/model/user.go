@@ -16,3 +16,24 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+// UserPage 用户分页列表（v1）
+type UserPage struct {
+	Users []User `json:"users"`
+	Total int64  `json:"total"`
+	Page  int    `json:"page"`
+	Size  int    `json:"size"`
+}
+
+// Pagination 分页信息
+type Pagination struct {
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+	Total    int64 `json:"total"`
+}
+
+// UserListResponse 用户列表响应（v2）
+type UserListResponse struct {
+	Items      []User     `json:"items"`
+	Pagination Pagination `json:"pagination"`
+}
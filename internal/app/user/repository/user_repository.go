@@ -30,7 +30,7 @@ func NewUserRepository(db *database.DB) UserRepository {
 
 // Create 创建用户
 func (r *userRepository) Create(ctx context.Context, user *model.User) error {
-	if err := r.db.Create(user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 	return nil
@@ -39,7 +39,7 @@ func (r *userRepository) Create(ctx context.Context, user *model.User) error {
 // GetByID 根据ID获取用户
 func (r *userRepository) GetByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
 		if err == database.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -51,7 +51,7 @@ func (r *userRepository) GetByID(ctx context.Context, id uint) (*model.User, err
 // GetByEmail 根据邮箱获取用户
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
 		if err == database.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -62,7 +62,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.U
 
 // Update 更新用户
 func (r *userRepository) Update(ctx context.Context, user *model.User) error {
-	if err := r.db.Save(user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 	return nil
@@ -70,7 +70,7 @@ func (r *userRepository) Update(ctx context.Context, user *model.User) error {
 
 // Delete 删除用户
 func (r *userRepository) Delete(ctx context.Context, id uint) error {
-	if err := r.db.Delete(&model.User{}, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Delete(&model.User{}, id).Error; err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 	return nil
@@ -84,12 +84,12 @@ func (r *userRepository) List(ctx context.Context, page, pageSize int) ([]model.
 	offset := (page - 1) * pageSize
 
 	// 获取总数
-	if err := r.db.Model(&model.User{}).Count(&total).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&model.User{}).Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count users: %w", err)
 	}
 
 	// 获取分页数据
-	if err := r.db.Offset(offset).Limit(pageSize).Find(&users).Error; err != nil {
+	if err := r.db.WithContext(ctx).Offset(offset).Limit(pageSize).Find(&users).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to list users: %w", err)
 	}
 
@@ -1,12 +1,14 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"go-api-mono/internal/app/user/model"
 	"go-api-mono/internal/app/user/repository"
 	"go-api-mono/internal/pkg/core"
 	"go-api-mono/internal/pkg/errors"
+	"go-api-mono/internal/pkg/tracing"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -33,11 +35,11 @@ func (s *UserService) RegisterUser(ctx *core.Context, user *model.User) error {
 	}
 
 	// 加密密码
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := hashPassword(ctx.Request.Context(), user.Password)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 
 	// 创建用户
 	if err := s.repo.Create(ctx.Request.Context(), user); err != nil {
@@ -59,7 +61,7 @@ func (s *UserService) AuthenticateUser(ctx *core.Context, email, password string
 	}
 
 	// 验证密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+	if err := comparePassword(ctx.Request.Context(), user.Password, password); err != nil {
 		return nil, errors.ErrInvalidCredentials
 	}
 
@@ -91,11 +93,11 @@ func (s *UserService) UpdateUser(ctx *core.Context, user *model.User) error {
 
 	// 如果提供了新密码，则加密
 	if user.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+		hashedPassword, err := hashPassword(ctx.Request.Context(), user.Password)
 		if err != nil {
 			return fmt.Errorf("failed to hash password: %w", err)
 		}
-		user.Password = string(hashedPassword)
+		user.Password = hashedPassword
 	} else {
 		user.Password = existingUser.Password
 	}
@@ -141,3 +143,23 @@ func (s *UserService) ListUsers(ctx *core.Context, page, pageSize int) ([]model.
 
 	return users, total, nil
 }
+
+// hashPassword 加密密码，bcrypt 耗时较长，单独记录span
+func hashPassword(ctx context.Context, password string) (string, error) {
+	_, span := tracing.Start(ctx, "bcrypt.GenerateFromPassword")
+	defer span.End()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// comparePassword 校验密码是否与哈希匹配
+func comparePassword(ctx context.Context, hashed, password string) error {
+	_, span := tracing.Start(ctx, "bcrypt.CompareHashAndPassword")
+	defer span.End()
+
+	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password))
+}
@@ -23,9 +23,10 @@ type Config struct {
 
 // Claims 自定义JWT声明
 type Claims struct {
-	UserID   uint   `json:"user_id"`  // 用户ID
-	Username string `json:"username"` // 用户名
-	Role     string `json:"role"`     // 用户角色
+	UserID   uint   `json:"user_id"`        // 用户ID
+	Username string `json:"username"`       // 用户名
+	Role     string `json:"role"`           // 用户角色
+	CSRF     string `json:"csrf,omitempty"` // Cookie 会话绑定的 CSRF 令牌
 	jwt.RegisteredClaims
 }
 
@@ -60,11 +61,30 @@ func New(config Config) *JWT {
 
 // GenerateToken 生成JWT令牌
 func (j *JWT) GenerateToken(userID uint, username, role string) (string, error) {
+	return j.generate(userID, username, role, "")
+}
+
+// GenerateSessionToken 生成用于 Cookie 会话的JWT令牌及与之绑定的 CSRF 令牌
+func (j *JWT) GenerateSessionToken(userID uint, username, role string) (string, string, error) {
+	csrf, err := NewCSRFToken()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate csrf token: %w", err)
+	}
+	token, err := j.generate(userID, username, role, csrf)
+	if err != nil {
+		return "", "", err
+	}
+	return token, csrf, nil
+}
+
+// generate 签发JWT令牌
+func (j *JWT) generate(userID uint, username, role, csrf string) (string, error) {
 	now := time.Now()
 	claims := Claims{
 		UserID:   userID,
 		Username: username,
 		Role:     role,
+		CSRF:     csrf,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(j.Config.ExpirationTime)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// ModeBearer 令牌通过响应体返回，客户端以 Authorization 请求头携带
+	ModeBearer = "bearer"
+	// ModeCookie 令牌写入 HttpOnly Cookie，需配合 CSRF 防护
+	ModeCookie = "cookie"
+)
+
+// SessionConfig 会话配置
+type SessionConfig struct {
+	DefaultMode  string            // 未识别客户端类型时使用的模式
+	ClientHeader string            // 标识客户端类型的请求头，如 X-Client-Type
+	Clients      map[string]string // 客户端类型 -> 模式
+
+	CookieName   string        // 保存令牌的 Cookie 名称
+	CookieDomain string        // Cookie 域
+	CookiePath   string        // Cookie 路径
+	Secure       bool          // 是否仅通过 HTTPS 发送
+	SameSite     http.SameSite // SameSite 属性
+
+	CSRFCookieName string // 保存 CSRF 令牌的 Cookie 名称，前端脚本可读取
+	CSRFHeaderName string // 携带 CSRF 令牌的请求头名称
+}
+
+// Sessions 根据客户端类型选择认证模式并管理会话 Cookie
+type Sessions struct {
+	config SessionConfig
+}
+
+// NewSessions 创建会话管理器
+func NewSessions(config SessionConfig) *Sessions {
+	// 设置默认值
+	if config.DefaultMode == "" {
+		config.DefaultMode = ModeBearer
+	}
+	if config.ClientHeader == "" {
+		config.ClientHeader = "X-Client-Type"
+	}
+	if config.CookieName == "" {
+		config.CookieName = "session"
+	}
+	if config.CookiePath == "" {
+		config.CookiePath = "/"
+	}
+	if config.SameSite == 0 {
+		config.SameSite = http.SameSiteLaxMode
+	}
+	if config.CSRFCookieName == "" {
+		config.CSRFCookieName = "csrf_token"
+	}
+	if config.CSRFHeaderName == "" {
+		config.CSRFHeaderName = "X-CSRF-Token"
+	}
+	return &Sessions{config: config}
+}
+
+// Config 返回会话配置
+func (s *Sessions) Config() SessionConfig {
+	return s.config
+}
+
+// Mode 根据请求的客户端类型返回认证模式
+func (s *Sessions) Mode(r *http.Request) string {
+	client := strings.ToLower(strings.TrimSpace(r.Header.Get(s.config.ClientHeader)))
+	if mode, ok := s.config.Clients[client]; ok && client != "" {
+		return mode
+	}
+	return s.config.DefaultMode
+}
+
+// SetCookies 写入会话 Cookie 与 CSRF Cookie
+// 会话 Cookie 为 HttpOnly，CSRF Cookie 需要被前端脚本读取后放入请求头
+func (s *Sessions) SetCookies(w http.ResponseWriter, token, csrf string, ttl time.Duration) {
+	expires := time.Now().Add(ttl)
+	http.SetCookie(w, s.cookie(s.config.CookieName, token, true, expires, int(ttl.Seconds())))
+	http.SetCookie(w, s.cookie(s.config.CSRFCookieName, csrf, false, expires, int(ttl.Seconds())))
+}
+
+// ClearCookies 清除会话 Cookie 与 CSRF Cookie
+func (s *Sessions) ClearCookies(w http.ResponseWriter) {
+	http.SetCookie(w, s.cookie(s.config.CookieName, "", true, time.Unix(0, 0), -1))
+	http.SetCookie(w, s.cookie(s.config.CSRFCookieName, "", false, time.Unix(0, 0), -1))
+}
+
+// cookie 创建具有统一属性的 Cookie
+func (s *Sessions) cookie(name, value string, httpOnly bool, expires time.Time, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   s.config.CookieDomain,
+		Path:     s.config.CookiePath,
+		Expires:  expires,
+		MaxAge:   maxAge,
+		Secure:   s.config.Secure,
+		HttpOnly: httpOnly,
+		SameSite: s.config.SameSite,
+	}
+}
+
+// NewCSRFToken 生成随机的 CSRF 令牌
+func NewCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,189 @@
+package concurrency
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Priority 请求的优先级，决定负载过高时的拒绝顺序
+type Priority int
+
+const (
+	PriorityLow      Priority = iota // 低优先级，最先被拒绝
+	PriorityNormal                   // 普通优先级
+	PriorityCritical                 // 关键请求（认证、健康检查），始终放行
+)
+
+// priorities 全部优先级，用于统计与指标
+var priorities = []Priority{PriorityLow, PriorityNormal, PriorityCritical}
+
+// Priorities 返回全部优先级
+func Priorities() []Priority {
+	return append([]Priority(nil), priorities...)
+}
+
+// String 返回优先级名称
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// ParsePriority 解析优先级名称，空字符串表示普通优先级
+func ParsePriority(s string) (Priority, error) {
+	switch s {
+	case "low":
+		return PriorityLow, nil
+	case "", "normal":
+		return PriorityNormal, nil
+	case "critical":
+		return PriorityCritical, nil
+	default:
+		return PriorityNormal, fmt.Errorf("unknown priority %q", s)
+	}
+}
+
+// Options 并发限制器选项
+type Options struct {
+	InitialLimit int // 初始并发上限
+	MinLimit     int // 并发上限的下界
+	MaxLimit     int // 并发上限的上界，关键请求也不会超过该值
+	// LatencyThreshold 请求耗时超过该值时视为过载，收缩并发上限
+	LatencyThreshold time.Duration
+	// BackoffRatio 过载时并发上限乘以的系数，取值 (0, 1)
+	BackoffRatio float64
+	// LowPriorityShare 低优先级请求可占用的并发上限比例，取值 (0, 1]
+	LowPriorityShare float64
+}
+
+// DefaultOptions 默认的并发限制器选项
+var DefaultOptions = Options{
+	InitialLimit:     20,
+	MinLimit:         5,
+	MaxLimit:         500,
+	LatencyThreshold: time.Second,
+	BackoffRatio:     0.9,
+	LowPriorityShare: 0.5,
+}
+
+// Limiter 按 AIMD 算法自适应调整并发上限的限制器
+// 请求耗时正常且并发接近上限时上限加一，请求超时或耗时过长时上限按比例收缩；
+// 超出上限的请求立即被拒绝而不是排队等待
+type Limiter struct {
+	opts     Options
+	now      func() time.Time
+	mu       sync.Mutex
+	limit    int
+	inFlight int
+	rejected [PriorityCritical + 1]uint64
+}
+
+// NewLimiter 创建并发限制器，未设置的选项使用默认值
+func NewLimiter(opts Options) *Limiter {
+	if opts.MinLimit <= 0 {
+		opts.MinLimit = DefaultOptions.MinLimit
+	}
+	if opts.MaxLimit <= 0 {
+		opts.MaxLimit = DefaultOptions.MaxLimit
+	}
+	opts.MaxLimit = max(opts.MaxLimit, opts.MinLimit)
+	if opts.InitialLimit <= 0 {
+		opts.InitialLimit = DefaultOptions.InitialLimit
+	}
+	if opts.LatencyThreshold <= 0 {
+		opts.LatencyThreshold = DefaultOptions.LatencyThreshold
+	}
+	if opts.BackoffRatio <= 0 || opts.BackoffRatio >= 1 {
+		opts.BackoffRatio = DefaultOptions.BackoffRatio
+	}
+	if opts.LowPriorityShare <= 0 || opts.LowPriorityShare > 1 {
+		opts.LowPriorityShare = DefaultOptions.LowPriorityShare
+	}
+
+	return &Limiter{
+		opts:  opts,
+		now:   time.Now,
+		limit: min(max(opts.InitialLimit, opts.MinLimit), opts.MaxLimit),
+	}
+}
+
+// Token 已获得的并发配额，请求结束时必须调用 Release
+type Token struct {
+	limiter *Limiter
+	start   time.Time
+	once    sync.Once
+}
+
+// Acquire 为请求申请并发配额，超出当前优先级可用的上限时返回 false
+func (l *Limiter) Acquire(priority Priority) (*Token, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight >= l.capacity(priority) {
+		l.rejected[priority]++
+		return nil, false
+	}
+	l.inFlight++
+	return &Token{limiter: l, start: l.now()}, true
+}
+
+// capacity 返回指定优先级可用的并发上限
+func (l *Limiter) capacity(priority Priority) int {
+	switch priority {
+	case PriorityCritical:
+		return l.opts.MaxLimit
+	case PriorityLow:
+		return max(int(float64(l.limit)*l.opts.LowPriorityShare), 1)
+	default:
+		return l.limit
+	}
+}
+
+// release 释放并发配额，并根据请求耗时与结果调整并发上限
+func (l *Limiter) release(t *Token, dropped bool) {
+	latency := l.now().Sub(t.start)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if dropped || latency > l.opts.LatencyThreshold {
+		l.limit = max(int(float64(l.limit)*l.opts.BackoffRatio), l.opts.MinLimit)
+	} else if l.inFlight*2 >= l.limit {
+		// 仅在并发接近上限时增长，避免低负载时上限无限膨胀
+		l.limit = min(l.limit+1, l.opts.MaxLimit)
+	}
+	l.inFlight--
+}
+
+// Release 释放并发配额，重复调用不会产生影响
+// dropped 表示请求因超时或下游不可用而失败，与耗时过长同样视为过载
+func (t *Token) Release(dropped bool) {
+	t.once.Do(func() { t.limiter.release(t, dropped) })
+}
+
+// Limit 返回当前的并发上限
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// InFlight 返回正在处理的请求数
+func (l *Limiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
+
+// Rejected 返回指定优先级被拒绝的请求总数
+func (l *Limiter) Rejected(priority Priority) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rejected[priority]
+}
@@ -0,0 +1,135 @@
+package concurrency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLimiter 创建使用可控时钟的限制器
+func newTestLimiter(opts Options) (*Limiter, func(time.Duration)) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(opts)
+	l.now = func() time.Time { return now }
+	return l, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestLimiterPriorities(t *testing.T) {
+	l, _ := newTestLimiter(Options{InitialLimit: 4, MinLimit: 1, MaxLimit: 6, LowPriorityShare: 0.5})
+
+	// 低优先级最多占用一半的并发上限
+	var tokens []*Token
+	for i := 0; i < 2; i++ {
+		token, ok := l.Acquire(PriorityLow)
+		require.True(t, ok)
+		tokens = append(tokens, token)
+	}
+	_, ok := l.Acquire(PriorityLow)
+	assert.False(t, ok)
+
+	// 普通优先级可以使用剩余的并发
+	for i := 0; i < 2; i++ {
+		token, ok := l.Acquire(PriorityNormal)
+		require.True(t, ok)
+		tokens = append(tokens, token)
+	}
+	_, ok = l.Acquire(PriorityNormal)
+	assert.False(t, ok)
+
+	// 关键请求在达到上界前始终放行
+	for i := 0; i < 2; i++ {
+		token, ok := l.Acquire(PriorityCritical)
+		require.True(t, ok)
+		tokens = append(tokens, token)
+	}
+	_, ok = l.Acquire(PriorityCritical)
+	assert.False(t, ok)
+
+	assert.Equal(t, 6, l.InFlight())
+	assert.Equal(t, uint64(1), l.Rejected(PriorityLow))
+	assert.Equal(t, uint64(1), l.Rejected(PriorityNormal))
+	assert.Equal(t, uint64(1), l.Rejected(PriorityCritical))
+
+	// 重复释放不会重复计数
+	for _, token := range tokens {
+		token.Release(false)
+		token.Release(false)
+	}
+	assert.Equal(t, 0, l.InFlight())
+}
+
+func TestLimiterAIMD(t *testing.T) {
+	l, advance := newTestLimiter(Options{
+		InitialLimit:     10,
+		MinLimit:         2,
+		MaxLimit:         12,
+		LatencyThreshold: 100 * time.Millisecond,
+		BackoffRatio:     0.5,
+	})
+
+	// acquire 同时获取 n 个配额
+	acquire := func(n int) []*Token {
+		tokens := make([]*Token, n)
+		for i := range tokens {
+			token, ok := l.Acquire(PriorityNormal)
+			require.True(t, ok)
+			tokens[i] = token
+		}
+		return tokens
+	}
+
+	// 低负载时上限不增长
+	token := acquire(1)[0]
+	advance(10 * time.Millisecond)
+	token.Release(false)
+	assert.Equal(t, 10, l.Limit())
+
+	// 并发接近上限且耗时正常时逐个增长，不超过上界
+	for _, token := range acquire(10) {
+		token.Release(false)
+	}
+	assert.Equal(t, 12, l.Limit())
+
+	// 耗时过长时按比例收缩
+	token = acquire(1)[0]
+	advance(200 * time.Millisecond)
+	token.Release(false)
+	assert.Equal(t, 6, l.Limit())
+
+	// 请求失败时同样收缩，不低于下界
+	for i := 0; i < 3; i++ {
+		acquire(1)[0].Release(true)
+	}
+	assert.Equal(t, 2, l.Limit())
+}
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Priority
+		wantErr  bool
+	}{
+		{"", PriorityNormal, false},
+		{"low", PriorityLow, false},
+		{"normal", PriorityNormal, false},
+		{"critical", PriorityCritical, false},
+		{"urgent", PriorityNormal, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, err := ParsePriority(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, p)
+			if tt.input != "" {
+				assert.Equal(t, tt.input, p.String())
+			}
+		})
+	}
+}
@@ -3,7 +3,11 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/netip"
+	"net/url"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 
 	"go-api-mono/configs"
@@ -13,12 +17,28 @@ import (
 
 // Config 应用程序配置
 type Config struct {
-	App       AppConfig       `yaml:"app"`
-	Server    ServerConfig    `yaml:"server"`
-	Log       LogConfig       `yaml:"log"`
-	Database  DatabaseConfig  `yaml:"database"`
-	JWT       JWTConfig       `yaml:"jwt"`
-	RateLimit RateLimitConfig `yaml:"rateLimit"`
+	App             AppConfig             `yaml:"app"`
+	Server          ServerConfig          `yaml:"server"`
+	Log             LogConfig             `yaml:"log"`
+	Database        DatabaseConfig        `yaml:"database"`
+	JWT             JWTConfig             `yaml:"jwt"`
+	APIKey          APIKeyConfig          `yaml:"apiKey"`
+	Redis           RedisConfig           `yaml:"redis"`
+	RateLimit       RateLimitConfig       `yaml:"rateLimit"`
+	Concurrency     ConcurrencyConfig     `yaml:"concurrency"`
+	Idempotency     IdempotencyConfig     `yaml:"idempotency"`
+	API             APIConfig             `yaml:"api"`
+	Validation      ValidationConfig      `yaml:"validation"`
+	Health          HealthConfig          `yaml:"health"`
+	Metrics         MetricsConfig         `yaml:"metrics"`
+	Tracing         TracingConfig         `yaml:"tracing"`
+	ErrorReporting  ErrorReportingConfig  `yaml:"errorReporting"`
+	BodyCapture     BodyCaptureConfig     `yaml:"bodyCapture"`
+	CORS            CORSConfig            `yaml:"cors"`
+	SecurityHeaders SecurityHeadersConfig `yaml:"securityHeaders"`
+	Session         SessionConfig         `yaml:"session"`
+	Reload          ReloadConfig          `yaml:"reload"`
+	Features        map[string]bool       `yaml:"features"` // 功能开关，可热更新
 }
 
 // AppConfig 应用程序基本配置
@@ -33,17 +53,30 @@ type ServerConfig struct {
 	Port            int           `yaml:"port"`
 	ReadTimeout     time.Duration `yaml:"readTimeout"`
 	WriteTimeout    time.Duration `yaml:"writeTimeout"`
-	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"` // 优雅关闭的总时长上限，包括 DrainDelay
+	DrainDelay      time.Duration `yaml:"drainDelay"`      // 就绪检查失败后继续处理请求的时间
+	UpgradeTimeout  time.Duration `yaml:"upgradeTimeout"`  // 升级时等待新进程就绪的时长上限
+	MaxBodySize     int64         `yaml:"maxBodySize"`     // 默认请求体大小上限（字节）
+	HandlerTimeout  time.Duration `yaml:"handlerTimeout"`  // 默认处理器超时时间
+	TrustedProxies  []string      `yaml:"trustedProxies"`  // 受信任的反向代理，CIDR 或单个IP
 }
 
 // LogConfig 日志配置
 type LogConfig struct {
-	Level      string `yaml:"level"`
-	Filename   string `yaml:"filename"`
-	MaxSize    int    `yaml:"maxSize"`
-	MaxBackups int    `yaml:"maxBackups"`
-	MaxAge     int    `yaml:"maxAge"`
-	Compress   bool   `yaml:"compress"`
+	Level      string          `yaml:"level"`
+	Filename   string          `yaml:"filename"`
+	MaxSize    int             `yaml:"maxSize"`
+	MaxBackups int             `yaml:"maxBackups"`
+	MaxAge     int             `yaml:"maxAge"`
+	Compress   bool            `yaml:"compress"`
+	Access     AccessLogConfig `yaml:"access"`
+}
+
+// AccessLogConfig 访问日志配置
+type AccessLogConfig struct {
+	Format        string        `yaml:"format"`        // 日志格式：json、common 或 combined
+	SlowThreshold time.Duration `yaml:"slowThreshold"` // 慢请求阈值，超过时以 Warn 级别记录，为0时不检测
+	SampleRate    float64       `yaml:"sampleRate"`    // 2xx 响应的记录比例，取值 (0, 1]
 }
 
 // DatabaseConfig 数据库配置
@@ -67,10 +100,199 @@ type JWTConfig struct {
 	TokenPrefix    string        `yaml:"tokenPrefix"`
 }
 
+// APIKeyConfig API Key 认证配置，识别出的 API Key 用于按 API Key 限流
+type APIKeyConfig struct {
+	Header string   `yaml:"header"` // 携带 API Key 的请求头
+	Keys   []string `yaml:"keys"`   // 有效的 API Key，携带其他取值的请求返回 401
+}
+
+// RedisConfig Redis配置
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+}
+
 // RateLimitConfig 限流配置
 type RateLimitConfig struct {
-	Requests int `yaml:"requests"`
-	Burst    int `yaml:"burst"`
+	Requests  int    `yaml:"requests"`
+	Burst     int    `yaml:"burst"`
+	Algorithm string `yaml:"algorithm"` // 限流算法：token_bucket、gcra 或 sliding_window
+	Store     string `yaml:"store"`     // 存储后端：memory 或 redis，redis 在所有副本间共享计数
+	KeyPrefix string `yaml:"keyPrefix"` // Redis 中限流键的前缀
+	// 以下选项仅用于内存存储
+	MaxEntries int           `yaml:"maxEntries"` // 最多保存的客户端数量，超出时淘汰最久未使用的客户端
+	IdleTTL    time.Duration `yaml:"idleTTL"`    // 客户端空闲超过该时长后释放其限流状态
+
+	Policies map[string]RateLimitPolicyConfig `yaml:"policies"` // 命名的限流策略，未定义 default 时由 requests 与 burst 生成
+	Routes   []RateLimitRouteConfig           `yaml:"routes"`   // 路由与策略的绑定，未绑定的路由使用 default 策略
+}
+
+// RateLimitQuotaConfig 限流配额
+type RateLimitQuotaConfig struct {
+	Requests int `yaml:"requests"` // 请求数/秒
+	Burst    int `yaml:"burst"`    // 突发请求数
+}
+
+// RateLimitPolicyConfig 限流策略配置
+type RateLimitPolicyConfig struct {
+	RateLimitQuotaConfig `yaml:",inline"`
+	KeyBy                []string                        `yaml:"keyBy"` // 限流键的组成：ip、user、apiKey（apiKey.keys 中的 API Key）
+	Roles                map[string]RateLimitQuotaConfig `yaml:"roles"` // 按角色覆盖配额
+}
+
+// RateLimitRouteConfig 路由限流绑定配置
+type RateLimitRouteConfig struct {
+	Route  string `yaml:"route"`  // 路由模式，如 "GET /api/v1/users"，省略版本段时匹配所有版本
+	Policy string `yaml:"policy"` // 策略名称
+	Cost   int    `yaml:"cost"`   // 每个请求消耗的配额，默认为1
+}
+
+// ConcurrencyConfig 自适应并发限制配置
+type ConcurrencyConfig struct {
+	Enabled          bool                     `yaml:"enabled"`          // 是否启用并发限制
+	InitialLimit     int                      `yaml:"initialLimit"`     // 初始并发上限
+	MinLimit         int                      `yaml:"minLimit"`         // 并发上限的下界
+	MaxLimit         int                      `yaml:"maxLimit"`         // 并发上限的上界，关键请求也不会超过该值
+	LatencyThreshold time.Duration            `yaml:"latencyThreshold"` // 请求耗时超过该值时收缩并发上限
+	BackoffRatio     float64                  `yaml:"backoffRatio"`     // 收缩时并发上限乘以的系数
+	LowPriorityShare float64                  `yaml:"lowPriorityShare"` // 低优先级请求可占用的并发上限比例
+	RetryAfter       time.Duration            `yaml:"retryAfter"`       // 被拒绝的请求建议的重试间隔
+	Routes           []ConcurrencyRouteConfig `yaml:"routes"`           // 路由与优先级的绑定，未绑定的路由为 normal
+}
+
+// ConcurrencyRouteConfig 路由优先级绑定配置
+type ConcurrencyRouteConfig struct {
+	Route    string `yaml:"route"`    // 路由模式，规则与限流路由绑定相同
+	Priority string `yaml:"priority"` // 优先级：low、normal 或 critical
+}
+
+// IdempotencyConfig 幂等配置
+type IdempotencyConfig struct {
+	Store string        `yaml:"store"` // 存储后端：memory 或 database
+	TTL   time.Duration `yaml:"ttl"`   // 幂等记录保留时间
+}
+
+// APIConfig API版本配置
+type APIConfig struct {
+	DefaultVersion string                       `yaml:"defaultVersion"` // 请求未指定版本时使用的版本
+	Deprecations   map[string]DeprecationConfig `yaml:"deprecations"`   // 已弃用的版本
+}
+
+// DeprecationConfig 版本弃用配置
+type DeprecationConfig struct {
+	Since  time.Time `yaml:"since"`  // 弃用生效时间
+	Sunset time.Time `yaml:"sunset"` // 计划下线时间
+	Link   string    `yaml:"link"`   // 迁移说明文档地址
+}
+
+// ValidationConfig 基于 OpenAPI 文档的校验配置
+type ValidationConfig struct {
+	Requests  bool `yaml:"requests"`  // 是否校验请求
+	Responses bool `yaml:"responses"` // 是否校验响应（建议仅在测试环境开启）
+}
+
+// HealthConfig 健康检查配置
+type HealthConfig struct {
+	Timeout  time.Duration `yaml:"timeout"`  // 单项检查超时时间
+	CacheTTL time.Duration `yaml:"cacheTTL"` // 检查结果缓存时间
+}
+
+// MetricsConfig 指标配置
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"` // 是否暴露指标
+	Path    string `yaml:"path"`    // 指标路径
+	Port    int    `yaml:"port"`    // 独立的管理端口，为0时与业务共用端口
+}
+
+// TracingConfig 链路追踪配置
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`     // 是否导出链路数据
+	Endpoint    string  `yaml:"endpoint"`    // OTLP/HTTP 接收端地址
+	Insecure    bool    `yaml:"insecure"`    // 是否使用 HTTP 而非 HTTPS
+	SampleRatio float64 `yaml:"sampleRatio"` // 根链路的采样比例，0~1
+}
+
+// ErrorReportingConfig 错误上报配置
+type ErrorReportingConfig struct {
+	SentryDSN string        `yaml:"sentryDSN"` // Sentry 项目 DSN，为空时恢复的 panic 仅记录日志
+	Timeout   time.Duration `yaml:"timeout"`   // 单次上报的超时时间
+}
+
+// BodyCaptureConfig 请求体与响应体记录配置，命中 routes、users 或 apiKeys 任一项的请求被记录
+// 收到 SIGHUP 时重新加载记录目标，可在运行期间为单个用户或 API Key 开启
+type BodyCaptureConfig struct {
+	MaxSize int      `yaml:"maxSize"` // 每个请求体与响应体最多记录的字节数
+	Redact  []string `yaml:"redact"`  // 额外需要隐藏的 JSON 字段路径，如 $.card.number
+	Routes  []string `yaml:"routes"`  // 路由模式，格式同 rateLimit.routes
+	Users   []string `yaml:"users"`   // 用户ID
+	APIKeys []string `yaml:"apiKeys"` // API Key，读取 apiKeyHeader 请求头
+
+	APIKeyHeader string `yaml:"apiKeyHeader"` // 按 API Key 记录时读取的请求头
+}
+
+// CORSConfig 跨域配置
+type CORSConfig struct {
+	CORSPolicyConfig `yaml:",inline"`
+
+	// 路由组前缀 -> 覆盖的策略，按最长前缀匹配，覆盖的策略整体替换默认策略
+	Overrides map[string]CORSPolicyConfig `yaml:"overrides"`
+}
+
+// CORSPolicyConfig 跨域策略配置
+type CORSPolicyConfig struct {
+	AllowOrigins     []string      `yaml:"allowOrigins"`     // 允许的来源：精确来源、https://*.example.com 或以 ^ 开头的正则
+	AllowMethods     []string      `yaml:"allowMethods"`     // 允许的请求方法
+	AllowHeaders     []string      `yaml:"allowHeaders"`     // 允许的请求头
+	ExposeHeaders    []string      `yaml:"exposeHeaders"`    // 允许浏览器读取的响应头
+	AllowCredentials bool          `yaml:"allowCredentials"` // 是否允许携带凭证
+	MaxAge           time.Duration `yaml:"maxAge"`           // 预检结果缓存时间
+}
+
+// SecurityHeadersConfig 安全响应头配置
+// 各字段为完整的响应头取值，未配置时使用运行模式对应的默认值，"off" 表示不发送
+type SecurityHeadersConfig struct {
+	StrictTransportSecurity   string `yaml:"strictTransportSecurity"`
+	ContentSecurityPolicy     string `yaml:"contentSecurityPolicy"`
+	ContentTypeOptions        string `yaml:"contentTypeOptions"`
+	FrameOptions              string `yaml:"frameOptions"`
+	ReferrerPolicy            string `yaml:"referrerPolicy"`
+	PermissionsPolicy         string `yaml:"permissionsPolicy"`
+	CrossOriginOpenerPolicy   string `yaml:"crossOriginOpenerPolicy"`
+	CrossOriginResourcePolicy string `yaml:"crossOriginResourcePolicy"`
+	CrossOriginEmbedderPolicy string `yaml:"crossOriginEmbedderPolicy"`
+}
+
+// ReloadConfig 配置热更新
+// 收到 SIGHUP 时始终重新加载配置；开启 watch 且通过 CONFIG_FILE 指定了配置文件时，文件内容变化后同样重新加载
+type ReloadConfig struct {
+	Watch    bool          `yaml:"watch"`    // 是否监视配置文件
+	Interval time.Duration `yaml:"interval"` // 检查配置文件的间隔
+}
+
+// SessionConfig 认证会话配置
+type SessionConfig struct {
+	DefaultMode  string              `yaml:"defaultMode"`  // 默认认证模式：bearer 或 cookie
+	ClientHeader string              `yaml:"clientHeader"` // 标识客户端类型的请求头
+	Clients      map[string]string   `yaml:"clients"`      // 客户端类型 -> 认证模式
+	Cookie       SessionCookieConfig `yaml:"cookie"`
+	CSRF         CSRFConfig          `yaml:"csrf"`
+}
+
+// SessionCookieConfig 会话 Cookie 配置
+type SessionCookieConfig struct {
+	Name     string `yaml:"name"`     // Cookie 名称
+	Domain   string `yaml:"domain"`   // Cookie 域
+	Path     string `yaml:"path"`     // Cookie 路径
+	Secure   bool   `yaml:"secure"`   // 是否仅通过 HTTPS 发送
+	SameSite string `yaml:"sameSite"` // SameSite 属性：lax、strict 或 none
+}
+
+// CSRFConfig CSRF 防护配置
+type CSRFConfig struct {
+	CookieName     string   `yaml:"cookieName"`     // CSRF 令牌 Cookie 名称
+	HeaderName     string   `yaml:"headerName"`     // CSRF 令牌请求头名称
+	TrustedOrigins []string `yaml:"trustedOrigins"` // 除同源外允许发起写请求的来源
 }
 
 // Load 加载配置
@@ -128,6 +350,15 @@ func setDefaults(config *Config) {
 	if config.Server.ShutdownTimeout == 0 {
 		config.Server.ShutdownTimeout = 30 * time.Second
 	}
+	if config.Server.UpgradeTimeout == 0 {
+		config.Server.UpgradeTimeout = 30 * time.Second
+	}
+	if config.Server.MaxBodySize == 0 {
+		config.Server.MaxBodySize = 1 << 20
+	}
+	if config.Server.HandlerTimeout == 0 {
+		config.Server.HandlerTimeout = 5 * time.Second
+	}
 
 	if config.Log.Level == "" {
 		config.Log.Level = "debug"
@@ -135,6 +366,15 @@ func setDefaults(config *Config) {
 	if config.Log.Filename == "" {
 		config.Log.Filename = "logs/app.log"
 	}
+	if config.Log.Access.Format == "" {
+		config.Log.Access.Format = "json"
+	}
+	if config.Log.Access.SlowThreshold == 0 {
+		config.Log.Access.SlowThreshold = time.Second
+	}
+	if config.Log.Access.SampleRate == 0 {
+		config.Log.Access.SampleRate = 1
+	}
 
 	if config.JWT.SigningMethod == "" {
 		config.JWT.SigningMethod = "HS256"
@@ -146,12 +386,119 @@ func setDefaults(config *Config) {
 		config.JWT.ExpirationTime = 24 * time.Hour
 	}
 
+	if config.APIKey.Header == "" {
+		config.APIKey.Header = "X-API-Key"
+	}
+
 	if config.RateLimit.Requests == 0 {
 		config.RateLimit.Requests = 100
 	}
 	if config.RateLimit.Burst == 0 {
 		config.RateLimit.Burst = 200
 	}
+	if config.RateLimit.Algorithm == "" {
+		config.RateLimit.Algorithm = "token_bucket"
+	}
+	if config.RateLimit.Store == "" {
+		config.RateLimit.Store = "memory"
+	}
+	if config.RateLimit.KeyPrefix == "" {
+		config.RateLimit.KeyPrefix = "ratelimit:"
+	}
+	if config.RateLimit.MaxEntries == 0 {
+		config.RateLimit.MaxEntries = 100000
+	}
+	if config.RateLimit.IdleTTL == 0 {
+		config.RateLimit.IdleTTL = 10 * time.Minute
+	}
+
+	if config.Concurrency.InitialLimit == 0 {
+		config.Concurrency.InitialLimit = 20
+	}
+	if config.Concurrency.MinLimit == 0 {
+		config.Concurrency.MinLimit = 5
+	}
+	if config.Concurrency.MaxLimit == 0 {
+		config.Concurrency.MaxLimit = 500
+	}
+	if config.Concurrency.LatencyThreshold == 0 {
+		config.Concurrency.LatencyThreshold = time.Second
+	}
+	if config.Concurrency.BackoffRatio == 0 {
+		config.Concurrency.BackoffRatio = 0.9
+	}
+	if config.Concurrency.LowPriorityShare == 0 {
+		config.Concurrency.LowPriorityShare = 0.5
+	}
+	if config.Concurrency.RetryAfter == 0 {
+		config.Concurrency.RetryAfter = time.Second
+	}
+
+	if config.Idempotency.Store == "" {
+		config.Idempotency.Store = "memory"
+	}
+	if config.Idempotency.TTL == 0 {
+		config.Idempotency.TTL = 24 * time.Hour
+	}
+
+	if config.API.DefaultVersion == "" {
+		config.API.DefaultVersion = "v1"
+	}
+
+	if config.Health.Timeout == 0 {
+		config.Health.Timeout = 2 * time.Second
+	}
+	if config.Health.CacheTTL == 0 {
+		config.Health.CacheTTL = 5 * time.Second
+	}
+
+	if config.Metrics.Path == "" {
+		config.Metrics.Path = "/metrics"
+	}
+
+	if config.Tracing.Endpoint == "" {
+		config.Tracing.Endpoint = "localhost:4318"
+	}
+	if config.Tracing.SampleRatio == 0 {
+		config.Tracing.SampleRatio = 1
+	}
+
+	if config.ErrorReporting.Timeout == 0 {
+		config.ErrorReporting.Timeout = 5 * time.Second
+	}
+
+	if config.BodyCapture.MaxSize == 0 {
+		config.BodyCapture.MaxSize = 4 << 10
+	}
+	if config.BodyCapture.APIKeyHeader == "" {
+		config.BodyCapture.APIKeyHeader = "X-API-Key"
+	}
+
+	if config.Reload.Interval == 0 {
+		config.Reload.Interval = 5 * time.Second
+	}
+
+	if config.Session.DefaultMode == "" {
+		config.Session.DefaultMode = "bearer"
+	}
+	if config.Session.ClientHeader == "" {
+		config.Session.ClientHeader = "X-Client-Type"
+	}
+	if config.Session.Cookie.Name == "" {
+		config.Session.Cookie.Name = "session"
+	}
+	if config.Session.Cookie.Path == "" {
+		config.Session.Cookie.Path = "/"
+	}
+	if config.Session.Cookie.SameSite == "" {
+		config.Session.Cookie.SameSite = "lax"
+	}
+	if config.Session.CSRF.CookieName == "" {
+		config.Session.CSRF.CookieName = "csrf_token"
+	}
+	if config.Session.CSRF.HeaderName == "" {
+		config.Session.CSRF.HeaderName = "X-CSRF-Token"
+	}
 }
 
 // Validate 验证配置
@@ -166,6 +513,11 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("server config validation failed: %w", err)
 	}
 
+	// 日志配置验证
+	if err := c.validateLog(); err != nil {
+		return fmt.Errorf("log config validation failed: %w", err)
+	}
+
 	// 数据库配置验证
 	if err := c.validateDatabase(); err != nil {
 		return fmt.Errorf("database config validation failed: %w", err)
@@ -176,11 +528,78 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("jwt config validation failed: %w", err)
 	}
 
+	// API Key 配置验证
+	if err := c.validateAPIKey(); err != nil {
+		return fmt.Errorf("api key config validation failed: %w", err)
+	}
+
 	// 速率限制配置验证
 	if err := c.validateRateLimit(); err != nil {
 		return fmt.Errorf("rate limit config validation failed: %w", err)
 	}
 
+	// 并发限制配置验证
+	if err := c.validateConcurrency(); err != nil {
+		return fmt.Errorf("concurrency config validation failed: %w", err)
+	}
+
+	// 幂等配置验证
+	if err := c.validateIdempotency(); err != nil {
+		return fmt.Errorf("idempotency config validation failed: %w", err)
+	}
+
+	// API版本配置验证
+	if err := c.validateAPI(); err != nil {
+		return fmt.Errorf("api config validation failed: %w", err)
+	}
+
+	// 健康检查配置验证
+	if err := c.validateHealth(); err != nil {
+		return fmt.Errorf("health config validation failed: %w", err)
+	}
+
+	// 指标配置验证
+	if err := c.validateMetrics(); err != nil {
+		return fmt.Errorf("metrics config validation failed: %w", err)
+	}
+
+	// 链路追踪配置验证
+	if err := c.validateTracing(); err != nil {
+		return fmt.Errorf("tracing config validation failed: %w", err)
+	}
+
+	// 错误上报配置验证
+	if err := c.validateErrorReporting(); err != nil {
+		return fmt.Errorf("error reporting config validation failed: %w", err)
+	}
+
+	// 请求体记录配置验证
+	if err := c.validateBodyCapture(); err != nil {
+		return fmt.Errorf("body capture config validation failed: %w", err)
+	}
+
+	// 跨域配置验证
+	if err := c.validateCORS(); err != nil {
+		return fmt.Errorf("cors config validation failed: %w", err)
+	}
+
+	// 会话配置验证
+	if err := c.validateSession(); err != nil {
+		return fmt.Errorf("session config validation failed: %w", err)
+	}
+
+	// 热更新配置验证
+	if c.Reload.Interval <= 0 {
+		return errors.New("reload config validation failed: interval must be positive")
+	}
+
+	// 功能开关验证
+	for name := range c.Features {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("features config validation failed: feature name must not be empty")
+		}
+	}
+
 	return nil
 }
 
@@ -213,6 +632,32 @@ func (c *Config) validateServer() error {
 	if c.Server.ShutdownTimeout <= 0 {
 		return errors.New("server shutdown timeout must be positive")
 	}
+	if c.Server.DrainDelay < 0 {
+		return errors.New("server drain delay must not be negative")
+	}
+	if c.Server.DrainDelay >= c.Server.ShutdownTimeout {
+		return errors.New("server drain delay must be less than shutdown timeout")
+	}
+	if c.Server.UpgradeTimeout <= 0 {
+		return errors.New("server upgrade timeout must be positive")
+	}
+	if c.Server.MaxBodySize <= 0 {
+		return errors.New("server max body size must be positive")
+	}
+	if c.Server.HandlerTimeout <= 0 {
+		return errors.New("server handler timeout must be positive")
+	}
+	if c.Server.HandlerTimeout >= c.Server.WriteTimeout {
+		return errors.New("server handler timeout must be less than write timeout")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(proxy); err != nil {
+			return fmt.Errorf("server trusted proxy %q must be an IP address or CIDR", proxy)
+		}
+	}
 	return nil
 }
 
@@ -266,6 +711,15 @@ func (c *Config) validateJWT() error {
 	return nil
 }
 
+func (c *Config) validateAPIKey() error {
+	for _, key := range c.APIKey.Keys {
+		if strings.TrimSpace(key) == "" {
+			return errors.New("api key must not be empty")
+		}
+	}
+	return nil
+}
+
 func (c *Config) validateRateLimit() error {
 	if c.RateLimit.Requests <= 0 {
 		return errors.New("rate limit requests must be positive")
@@ -276,5 +730,273 @@ func (c *Config) validateRateLimit() error {
 	if c.RateLimit.Burst < c.RateLimit.Requests {
 		return errors.New("rate limit burst must be greater than or equal to requests")
 	}
+	if c.RateLimit.MaxEntries < 0 {
+		return errors.New("rate limit max entries must not be negative")
+	}
+	if c.RateLimit.IdleTTL < 0 {
+		return errors.New("rate limit idle ttl must not be negative")
+	}
+	switch c.RateLimit.Algorithm {
+	case "", "token_bucket", "gcra", "sliding_window":
+	default:
+		return errors.New("rate limit algorithm must be one of: token_bucket, gcra, sliding_window")
+	}
+	switch c.RateLimit.Store {
+	case "", "memory":
+	case "redis":
+		if c.Redis.Addr == "" {
+			return errors.New("redis addr is required when rate limit store is redis")
+		}
+	default:
+		return errors.New("rate limit store must be one of: memory, redis")
+	}
+
+	for name, policy := range c.RateLimit.Policies {
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("rate limit policy %s: %w", name, err)
+		}
+	}
+	for _, route := range c.RateLimit.Routes {
+		if route.Route == "" {
+			return errors.New("rate limit route is required")
+		}
+		policy, ok := c.RateLimit.AllPolicies()[route.Policy]
+		if !ok {
+			return fmt.Errorf("rate limit route %s: unknown policy %q", route.Route, route.Policy)
+		}
+		burst := policy.Burst
+		for _, quota := range policy.Roles {
+			burst = min(burst, quota.Burst)
+		}
+		if route.Cost < 0 || route.Cost > burst {
+			return fmt.Errorf("rate limit route %s: cost must be between 0 and the policy burst %d", route.Route, burst)
+		}
+	}
+	return nil
+}
+
+// AllPolicies 返回所有限流策略
+// 未定义 default 策略时，由 requests 与 burst 生成按IP限流的 default 策略
+func (c RateLimitConfig) AllPolicies() map[string]RateLimitPolicyConfig {
+	policies := make(map[string]RateLimitPolicyConfig, len(c.Policies)+1)
+	policies["default"] = RateLimitPolicyConfig{
+		RateLimitQuotaConfig: RateLimitQuotaConfig{Requests: c.Requests, Burst: c.Burst},
+		KeyBy:                []string{"ip"},
+	}
+	for name, policy := range c.Policies {
+		policies[name] = policy
+	}
+	return policies
+}
+
+// validate 验证限流策略
+func (p RateLimitPolicyConfig) validate() error {
+	if err := p.RateLimitQuotaConfig.validate(); err != nil {
+		return err
+	}
+	for _, key := range p.KeyBy {
+		if key != "ip" && key != "user" && key != "apiKey" {
+			return fmt.Errorf("keyBy must be one of: ip, user, apiKey, got %q", key)
+		}
+	}
+	for role, quota := range p.Roles {
+		if err := quota.validate(); err != nil {
+			return fmt.Errorf("role %s: %w", role, err)
+		}
+	}
+	return nil
+}
+
+// validate 验证限流配额
+func (q RateLimitQuotaConfig) validate() error {
+	if q.Requests <= 0 || q.Burst <= 0 {
+		return errors.New("requests and burst must be positive")
+	}
+	if q.Burst < q.Requests {
+		return errors.New("burst must be greater than or equal to requests")
+	}
+	return nil
+}
+
+func (c *Config) validateLog() error {
+	access := c.Log.Access
+	switch access.Format {
+	case "json", "common", "combined":
+	default:
+		return errors.New("access log format must be one of: json, common, combined")
+	}
+	if access.SlowThreshold < 0 {
+		return errors.New("access log slow threshold must not be negative")
+	}
+	if access.SampleRate <= 0 || access.SampleRate > 1 {
+		return errors.New("access log sample rate must be between 0 and 1")
+	}
+	return nil
+}
+
+func (c *Config) validateConcurrency() error {
+	cfg := c.Concurrency
+	if cfg.MinLimit <= 0 {
+		return errors.New("concurrency min limit must be positive")
+	}
+	if cfg.InitialLimit < cfg.MinLimit || cfg.InitialLimit > cfg.MaxLimit {
+		return errors.New("concurrency initial limit must be between min limit and max limit")
+	}
+	if cfg.LatencyThreshold <= 0 {
+		return errors.New("concurrency latency threshold must be positive")
+	}
+	if cfg.BackoffRatio <= 0 || cfg.BackoffRatio >= 1 {
+		return errors.New("concurrency backoff ratio must be between 0 and 1")
+	}
+	if cfg.LowPriorityShare <= 0 || cfg.LowPriorityShare > 1 {
+		return errors.New("concurrency low priority share must be between 0 and 1")
+	}
+	if cfg.RetryAfter < 0 {
+		return errors.New("concurrency retry after must not be negative")
+	}
+	for _, route := range cfg.Routes {
+		if route.Route == "" {
+			return errors.New("concurrency route is required")
+		}
+		switch route.Priority {
+		case "low", "normal", "critical":
+		default:
+			return fmt.Errorf("concurrency route %s: priority must be one of: low, normal, critical", route.Route)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateIdempotency() error {
+	if c.Idempotency.Store != "memory" && c.Idempotency.Store != "database" {
+		return errors.New("idempotency store must be one of: memory, database")
+	}
+	if c.Idempotency.TTL <= 0 {
+		return errors.New("idempotency ttl must be positive")
+	}
+	return nil
+}
+
+func (c *Config) validateAPI() error {
+	if c.API.DefaultVersion == "" {
+		return errors.New("api default version is required")
+	}
+	for version, d := range c.API.Deprecations {
+		if !d.Since.IsZero() && !d.Sunset.IsZero() && d.Sunset.Before(d.Since) {
+			return fmt.Errorf("api version %s sunset must not be before its deprecation date", version)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateHealth() error {
+	if c.Health.Timeout <= 0 {
+		return errors.New("health check timeout must be positive")
+	}
+	if c.Health.CacheTTL < 0 {
+		return errors.New("health check cache ttl must not be negative")
+	}
+	return nil
+}
+
+func (c *Config) validateMetrics() error {
+	if !strings.HasPrefix(c.Metrics.Path, "/") {
+		return errors.New("metrics path must start with /")
+	}
+	if c.Metrics.Port < 0 || c.Metrics.Port > 65535 {
+		return errors.New("invalid metrics port")
+	}
+	if c.Metrics.Port != 0 && c.Metrics.Port == c.Server.Port {
+		return errors.New("metrics port must differ from server port")
+	}
+	return nil
+}
+
+func (c *Config) validateTracing() error {
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return errors.New("tracing sample ratio must be between 0 and 1")
+	}
+	return nil
+}
+
+func (c *Config) validateErrorReporting() error {
+	if c.ErrorReporting.SentryDSN != "" {
+		u, err := url.Parse(c.ErrorReporting.SentryDSN)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.User.Username() == "" {
+			return errors.New("sentry dsn must look like https://<key>@<host>/<project>")
+		}
+	}
+	if c.ErrorReporting.Timeout < 0 {
+		return errors.New("error reporting timeout must not be negative")
+	}
+	return nil
+}
+
+func (c *Config) validateBodyCapture() error {
+	if c.BodyCapture.MaxSize <= 0 {
+		return errors.New("body capture max size must be positive")
+	}
+	for _, path := range c.BodyCapture.Redact {
+		if strings.Trim(path, "$.") == "" {
+			return fmt.Errorf("body capture redact path %q is empty", path)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateCORS() error {
+	if err := c.CORS.CORSPolicyConfig.validate(); err != nil {
+		return err
+	}
+	for prefix, policy := range c.CORS.Overrides {
+		if !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("cors override prefix %q must start with /", prefix)
+		}
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("cors override %s: %w", prefix, err)
+		}
+	}
+	return nil
+}
+
+// validate 验证跨域策略
+func (p CORSPolicyConfig) validate() error {
+	for _, origin := range p.AllowOrigins {
+		if origin == "*" && p.AllowCredentials {
+			return errors.New("allowOrigins \"*\" cannot be used with allowCredentials")
+		}
+		if strings.HasPrefix(origin, "^") {
+			if _, err := regexp.Compile(origin); err != nil {
+				return fmt.Errorf("invalid origin pattern %q: %w", origin, err)
+			}
+		}
+	}
+	if p.MaxAge < 0 {
+		return errors.New("maxAge must not be negative")
+	}
+	return nil
+}
+
+func (c *Config) validateSession() error {
+	validMode := func(mode string) bool {
+		return mode == "bearer" || mode == "cookie"
+	}
+	if !validMode(c.Session.DefaultMode) {
+		return fmt.Errorf("invalid default mode %q", c.Session.DefaultMode)
+	}
+	for client, mode := range c.Session.Clients {
+		if !validMode(mode) {
+			return fmt.Errorf("invalid mode %q for client %s", mode, client)
+		}
+	}
+	switch c.Session.Cookie.SameSite {
+	case "lax", "strict":
+	case "none":
+		if !c.Session.Cookie.Secure {
+			return errors.New("sameSite none requires secure cookies")
+		}
+	default:
+		return fmt.Errorf("invalid cookie sameSite %q", c.Session.Cookie.SameSite)
+	}
 	return nil
 }
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReloadablePaths 可在运行时重新加载的配置项，其余配置项的变化需要重启才能生效
+var ReloadablePaths = []string{
+	"log.level",
+	"rateLimit.requests",
+	"rateLimit.burst",
+	"rateLimit.policies",
+	"rateLimit.routes",
+	"bodyCapture.routes",
+	"bodyCapture.users",
+	"bodyCapture.apiKeys",
+	"cors",
+	"features",
+}
+
+// Changes 重新加载时发生变化的配置项，路径由 yaml 字段名组成，如 "server.port"
+type Changes struct {
+	Applied []string // 已生效的配置项
+	Ignored []string // 需要重启才能生效而被忽略的配置项
+}
+
+// Changed 判断 paths 中的任一配置项或其子项是否已生效
+func (c Changes) Changed(paths ...string) bool {
+	for _, applied := range c.Applied {
+		for _, path := range paths {
+			if hasPath(applied, path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasPath 判断 path 是否为 prefix 或其子项，如 "cors.allowOrigins" 属于 "cors"
+func hasPath(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+".")
+}
+
+// subscriber 配置变化的订阅者
+type subscriber struct {
+	name  string
+	paths []string
+	fn    func(cfg *Config) error
+}
+
+// Store 当前生效的配置，重新加载时原子地替换可重新加载的配置项并通知订阅者
+type Store struct {
+	load    func() (*Config, error)
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex // 串行化重新加载与订阅
+	subscribers []subscriber
+}
+
+// NewStore 以启动时加载的配置创建配置存储，重新加载时调用 Load
+func NewStore(cfg *Config) *Store {
+	s := &Store{load: Load}
+	s.current.Store(cfg)
+	return s
+}
+
+// Current 返回当前生效的配置，返回值不应被修改
+func (s *Store) Current() *Config {
+	return s.current.Load()
+}
+
+// Subscribe 订阅配置项的变化，paths 中任一配置项或其子项生效后以新的配置调用 fn
+func (s *Store) Subscribe(name string, fn func(cfg *Config) error, paths ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, subscriber{name: name, paths: paths, fn: fn})
+}
+
+// Reload 重新读取并校验配置，替换可重新加载的配置项后按订阅顺序通知订阅者
+// 新配置无效时保留原有配置并返回错误；订阅者失败时其余订阅者照常通知，错误一并返回
+func (s *Store) Reload() (Changes, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, err := s.load()
+	if err != nil {
+		return Changes{}, err
+	}
+
+	current := s.current.Load()
+	merged := *current
+	var changes Changes
+	mergeReloadable(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(next).Elem(), "", &changes)
+	if len(changes.Applied) == 0 {
+		return changes, nil
+	}
+	s.current.Store(&merged)
+
+	var errs []error
+	for _, sub := range s.subscribers {
+		if !changes.Changed(sub.paths...) {
+			continue
+		}
+		if err := sub.fn(&merged); err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply %s: %w", sub.name, err))
+		}
+	}
+	return changes, errors.Join(errs...)
+}
+
+// mergeReloadable 比较两份配置，将 next 中发生变化的可重新加载配置项复制到 dst
+// 结构体逐字段比较，内嵌的 inline 字段与外层位于同一路径，其余类型整体比较
+func mergeReloadable(dst, next reflect.Value, path string, changes *Changes) {
+	if dst.Kind() != reflect.Struct {
+		if reflect.DeepEqual(dst.Interface(), next.Interface()) {
+			return
+		}
+		if isReloadable(path) {
+			dst.Set(next)
+			changes.Applied = append(changes.Applied, path)
+		} else {
+			changes.Ignored = append(changes.Ignored, path)
+		}
+		return
+	}
+
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, inline := yamlName(field)
+		fieldPath := path
+		if !inline {
+			fieldPath = joinPath(path, name)
+		}
+		mergeReloadable(dst.Field(i), next.Field(i), fieldPath, changes)
+	}
+}
+
+// isReloadable 判断配置项是否可以重新加载
+func isReloadable(path string) bool {
+	for _, reloadable := range ReloadablePaths {
+		if hasPath(path, reloadable) {
+			return true
+		}
+	}
+	return false
+}
+
+// yamlName 返回字段的 yaml 名称以及是否为 inline 字段
+func yamlName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	name, opts, _ := strings.Cut(tag, ",")
+	if opts == "inline" {
+		return "", true
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return name, false
+}
+
+// joinPath 拼接配置项路径
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// FilePath 返回外部配置文件的路径，使用嵌入的配置文件时为空
+func FilePath() string {
+	return os.Getenv("CONFIG_FILE")
+}
+
+// Watch 每隔 interval 读取一次配置文件，内容变化时调用 onChange，直到 ctx 结束
+// 按内容而不是修改时间比较，编辑器以重命名方式保存或 Kubernetes 更新 ConfigMap 时同样能够发现
+func Watch(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	last, _ := os.ReadFile(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			data, err := os.ReadFile(path)
+			// 文件暂时不可读（如正在替换）时等待下一次检查
+			if err != nil || bytes.Equal(data, last) {
+				continue
+			}
+			last = data
+			onChange()
+		}
+	}
+}
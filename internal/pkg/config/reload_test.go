@@ -0,0 +1,158 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig 返回用于比较的配置
+func testConfig() *Config {
+	cfg := &Config{}
+	cfg.Server.Port = 8080
+	cfg.Log.Level = "info"
+	cfg.Database.Host = "localhost"
+	cfg.RateLimit.Requests = 10
+	cfg.RateLimit.Store = "memory"
+	cfg.CORS.AllowOrigins = []string{"https://app.example.com"}
+	return cfg
+}
+
+// newTestStore 创建重新加载时返回 next 的配置存储
+func newTestStore(next func() (*Config, error)) *Store {
+	s := NewStore(testConfig())
+	s.load = next
+	return s
+}
+
+func TestStoreReload(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(cfg *Config)
+		wantApplied []string
+		wantIgnored []string
+	}{
+		{
+			name:   "unchanged",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "reloadable sections",
+			modify: func(cfg *Config) {
+				cfg.Log.Level = "debug"
+				cfg.RateLimit.Requests = 20
+				cfg.CORS.AllowOrigins = []string{"https://admin.example.com"}
+				cfg.Features = map[string]bool{"newCheckout": true}
+			},
+			wantApplied: []string{"log.level", "rateLimit.requests", "cors.allowOrigins", "features"},
+		},
+		{
+			name: "restart required",
+			modify: func(cfg *Config) {
+				cfg.Server.Port = 9090
+				cfg.Log.Level = "warn"
+				cfg.Database.Host = "db.internal"
+				cfg.RateLimit.Store = "redis"
+			},
+			wantApplied: []string{"log.level"},
+			wantIgnored: []string{"server.port", "database.host", "rateLimit.store"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := testConfig()
+			tt.modify(next)
+			s := newTestStore(func() (*Config, error) { return next, nil })
+
+			changes, err := s.Reload()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantApplied, changes.Applied)
+			assert.Equal(t, tt.wantIgnored, changes.Ignored)
+
+			// 只有可重新加载的配置项生效
+			current := s.Current()
+			assert.Equal(t, next.Log.Level, current.Log.Level)
+			assert.Equal(t, next.CORS, current.CORS)
+			assert.Equal(t, 8080, current.Server.Port)
+			assert.Equal(t, "localhost", current.Database.Host)
+			assert.Equal(t, "memory", current.RateLimit.Store)
+		})
+	}
+}
+
+func TestStoreSubscribe(t *testing.T) {
+	next := testConfig()
+	s := newTestStore(func() (*Config, error) { return next, nil })
+
+	var notified []string
+	subscribe := func(name string, err error, paths ...string) {
+		s.Subscribe(name, func(cfg *Config) error {
+			assert.Same(t, s.Current(), cfg)
+			notified = append(notified, name)
+			return err
+		}, paths...)
+	}
+	subscribe("log-level", nil, "log.level")
+	subscribe("cors", errors.New("invalid policy"), "cors")
+	subscribe("rate-limits", nil, "rateLimit")
+
+	// 只通知订阅了发生变化的配置项的订阅者，失败的订阅者不影响其余订阅者
+	next.CORS.AllowOrigins = []string{"https://admin.example.com"}
+	next.RateLimit.Routes = []RateLimitRouteConfig{{Route: "POST /login", Policy: "login"}}
+	changes, err := s.Reload()
+	assert.EqualError(t, err, "failed to apply cors: invalid policy")
+	assert.Equal(t, []string{"cors", "rate-limits"}, notified)
+	assert.True(t, changes.Changed("rateLimit"))
+	assert.False(t, changes.Changed("log"))
+	assert.Equal(t, next.CORS.AllowOrigins, s.Current().CORS.AllowOrigins)
+}
+
+func TestStoreReloadInvalid(t *testing.T) {
+	s := newTestStore(func() (*Config, error) { return nil, errors.New("config validation failed") })
+	before := s.Current()
+
+	_, err := s.Reload()
+	assert.EqualError(t, err, "config validation failed")
+	assert.Same(t, before, s.Current())
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("log:\n  level: info\n"), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Watch(ctx, path, 5*time.Millisecond, func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+	}()
+
+	// 监视开始前的修改不会触发回调，持续修改直到被发现
+	deadline := time.After(time.Second)
+	for i := 0; ; i++ {
+		require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf("log:\n  level: debug # %d\n", i)), 0o644))
+		select {
+		case <-changed:
+			cancel()
+			<-done
+			return
+		case <-deadline:
+			t.Fatal("config change not detected")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientIPKey 客户端IP在上下文中的键
+type clientIPKey struct{}
+
+// ParseTrustedProxies 解析受信任代理列表，支持 CIDR 与单个IP
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// ClientIPResolver 根据受信任代理解析客户端IP
+//
+// 只有直接连接的对端属于受信任代理时才读取转发头，并从右向左跳过受信任代理，
+// 第一个不受信任的地址即为客户端IP，客户端自行伪造的左侧部分会被忽略。
+// 转发头优先使用 RFC 7239 的 Forwarded，其次是 X-Forwarded-For 与 X-Real-IP
+type ClientIPResolver struct {
+	trusted []netip.Prefix
+}
+
+// NewClientIPResolver 创建客户端IP解析器
+func NewClientIPResolver(trusted []netip.Prefix) *ClientIPResolver {
+	return &ClientIPResolver{trusted: trusted}
+}
+
+// Resolve 解析请求的客户端IP
+func (c *ClientIPResolver) Resolve(r *http.Request) string {
+	remote, ok := parseIP(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !c.isTrusted(remote) {
+		return remote.String()
+	}
+
+	var chain []string
+	switch {
+	case len(r.Header.Values("Forwarded")) > 0:
+		chain = forwardedFor(r.Header.Values("Forwarded"))
+	case len(r.Header.Values("X-Forwarded-For")) > 0:
+		chain = splitList(r.Header.Values("X-Forwarded-For"))
+	case r.Header.Get("X-Real-IP") != "":
+		chain = []string{r.Header.Get("X-Real-IP")}
+	}
+
+	// 从右向左跳过受信任代理；地址无法解析时无法继续判断，以最后一个受信任的跳为准
+	client := remote
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip, ok := parseIP(chain[i])
+		if !ok {
+			break
+		}
+		client = ip
+		if !c.isTrusted(ip) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// Handler 返回在请求上下文中记录客户端IP的处理器
+func (c *ClientIPResolver) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), clientIPKey{}, c.Resolve(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// isTrusted 判断地址是否属于受信任代理
+func (c *ClientIPResolver) isTrusted(ip netip.Addr) bool {
+	for _, prefix := range c.trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedFor 提取 Forwarded 头中各跳的 for 参数
+// 如：Forwarded: for=192.0.2.60;proto=http, for="[2001:db8:cafe::17]:4711"
+func forwardedFor(values []string) []string {
+	var chain []string
+	for _, element := range splitList(values) {
+		node := ""
+		for _, pair := range strings.Split(element, ";") {
+			key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+			if found && strings.EqualFold(key, "for") {
+				node = strings.Trim(value, `"`)
+			}
+		}
+		// 没有 for 参数的跳保留为空值，使解析在此处停止
+		chain = append(chain, node)
+	}
+	return chain
+}
+
+// splitList 拆分可能出现多次且以逗号分隔的请求头
+func splitList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+	}
+	return items
+}
+
+// parseIP 解析可能带端口或方括号的地址
+func parseIP(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// ClientIP 返回请求的客户端IP
+// 请求未经过 ClientIPResolver 时返回对端地址
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	if ip, ok := parseIP(r.RemoteAddr); ok {
+		return ip.String()
+	}
+	return r.RemoteAddr
+}
+
+// ClientIP 返回当前请求的客户端IP
+func (c *Context) ClientIP() string {
+	return ClientIP(c.Request)
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.10", "::1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", prefixes[0].String())
+	assert.Equal(t, "192.168.1.10/32", prefixes[1].String())
+	assert.Equal(t, "::1/128", prefixes[2].String())
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"proxy.local"})
+	assert.Error(t, err)
+}
+
+func TestClientIPResolver(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "2001:db8::/32"})
+	assert.NoError(t, err)
+	resolver := NewClientIPResolver(trusted)
+
+	tests := []struct {
+		name     string
+		remote   string
+		header   map[string][]string
+		expected string
+	}{
+		{name: "直连请求", remote: "203.0.113.7:5000", expected: "203.0.113.7"},
+		{name: "不受信任的对端忽略转发头", remote: "203.0.113.7:5000",
+			header: map[string][]string{"X-Forwarded-For": {"1.1.1.1"}, "X-Real-IP": {"1.1.1.1"}}, expected: "203.0.113.7"},
+		{name: "X-Forwarded-For从右向左解析", remote: "10.0.0.2:5000",
+			header: map[string][]string{"X-Forwarded-For": {"6.6.6.6, 198.51.100.1, 10.0.0.1"}}, expected: "198.51.100.1"},
+		{name: "多个X-Forwarded-For头", remote: "10.0.0.2:5000",
+			header: map[string][]string{"X-Forwarded-For": {"6.6.6.6", "198.51.100.1"}}, expected: "198.51.100.1"},
+		{name: "全部为受信任代理时取最左侧", remote: "10.0.0.2:5000",
+			header: map[string][]string{"X-Forwarded-For": {"10.1.1.1, 10.0.0.1"}}, expected: "10.1.1.1"},
+		{name: "无法解析的地址停在最后一个受信任的跳", remote: "10.0.0.2:5000",
+			header: map[string][]string{"X-Forwarded-For": {"198.51.100.1, garbage, 10.0.0.1"}}, expected: "10.0.0.1"},
+		{name: "X-Real-IP", remote: "10.0.0.2:5000",
+			header: map[string][]string{"X-Real-IP": {"198.51.100.1"}}, expected: "198.51.100.1"},
+		{name: "Forwarded优先于X-Forwarded-For", remote: "10.0.0.2:5000",
+			header: map[string][]string{
+				"Forwarded":       {`for=192.0.2.60;proto=https;by=10.0.0.2, for=10.0.0.1`},
+				"X-Forwarded-For": {"6.6.6.6"},
+			}, expected: "192.0.2.60"},
+		{name: "Forwarded中带端口的IPv6", remote: "[2001:db8::1]:443",
+			header: map[string][]string{"Forwarded": {`For="[2001:db8:cafe::17]:4711"`}}, expected: "2001:db8:cafe::17"},
+		{name: "Forwarded中的混淆标识", remote: "10.0.0.2:5000",
+			header: map[string][]string{"Forwarded": {`for=198.51.100.1, for=_hidden, for=10.0.0.1`}}, expected: "10.0.0.1"},
+		{name: "IPv4映射的IPv6对端", remote: "[::ffff:10.0.0.2]:5000",
+			header: map[string][]string{"X-Forwarded-For": {"198.51.100.1"}}, expected: "198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for k, values := range tt.header {
+				for _, v := range values {
+					req.Header.Add(k, v)
+				}
+			}
+			assert.Equal(t, tt.expected, resolver.Resolve(req))
+
+			var got string
+			resolver.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -25,6 +25,13 @@ func NewContext(r *http.Request, w http.ResponseWriter, log *logger.Logger) *Con
 	}
 }
 
+// SetRequest 替换当前请求，并同步上下文与响应处理器
+func (c *Context) SetRequest(r *http.Request) {
+	c.Request = r
+	c.Context = r.Context()
+	c.Response.Request = r
+}
+
 // WithValue 返回一个带有新值的上下文
 func (c *Context) WithValue(key, val interface{}) *Context {
 	return &Context{
@@ -68,3 +75,25 @@ func (c *Context) GetBool(key interface{}) bool {
 func (c *Context) GetInterface(key interface{}) interface{} {
 	return c.Value(key)
 }
+
+// RequestIDHeader 携带请求ID的请求头与响应头
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey 请求ID的上下文键
+type requestIDKey struct{}
+
+// WithRequestID 返回携带请求ID的上下文
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID 返回上下文中的请求ID，不存在时返回空字符串
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// RequestID 返回当前请求的ID
+func (c *Context) RequestID() string {
+	return RequestID(c)
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// NamedMiddleware 带名称的中间件，名称显示在路由表中
+type NamedMiddleware struct {
+	Name       string
+	Middleware Middleware
+}
+
+// named 以函数名为名称包装中间件
+func named(middlewares []Middleware) []NamedMiddleware {
+	result := make([]NamedMiddleware, 0, len(middlewares))
+	for _, m := range middlewares {
+		result = append(result, NamedMiddleware{Name: FuncName(m), Middleware: m})
+	}
+	return result
+}
+
+// FuncName 返回函数的简短名称，如 "middleware.JWT" 或 "controller.(*UserController).Login"
+// 闭包以创建它的函数命名，方法值去掉 "-fm" 后缀
+func FuncName(fn interface{}) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return ""
+	}
+
+	name := strings.TrimSuffix(f.Name(), "-fm")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	// 去掉 .func1、.func1.2 等闭包后缀
+	for {
+		i := strings.LastIndex(name, ".")
+		if i < 0 || !isClosureSuffix(name[i+1:]) {
+			return name
+		}
+		name = name[:i]
+	}
+}
+
+// isClosureSuffix 判断名称段是否为编译器生成的闭包编号
+func isClosureSuffix(s string) bool {
+	s = strings.TrimPrefix(s, "func")
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// register 向 ServeMux 注册处理器，模式冲突时记录错误并返回 false，而不是 panic
+func (s *Server) register(pattern string, handler HandlerFunc, route *Route) (ok bool) {
+	defer func() {
+		if p := recover(); p != nil {
+			s.errs = append(s.errs, fmt.Errorf("route %s (%s): %v", pattern, route.Handler, p))
+			ok = false
+		}
+	}()
+	s.mux.Handle(pattern, WrapHandler(handler, s.logger))
+	return true
+}
+
+// Err 返回注册路由时发现的冲突，如重复注册的方法与路径或无法区分的路径模式
+func (s *Server) Err() error {
+	return errors.Join(s.errs...)
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testController 用于检查方法值的名称
+type testController struct{}
+
+func (testController) Show(*Context) {}
+
+// tagMiddleware 创建一个不做任何处理的中间件，用于检查闭包的名称
+func tagMiddleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) { next(c) }
+	}
+}
+
+func TestFuncName(t *testing.T) {
+	assert.Equal(t, "core.tagMiddleware", FuncName(tagMiddleware()))
+	assert.Equal(t, "core.testController.Show", FuncName(testController{}.Show))
+	assert.Equal(t, "core.NewServer", FuncName(NewServer))
+	assert.Equal(t, "", FuncName(nil))
+	assert.Equal(t, "", FuncName("not a func"))
+}
+
+func TestRouteStack(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Use(tagMiddleware())
+	g := srv.Group("/users")
+	g.UseNamed(NamedMiddleware{Name: "auth", Middleware: tagMiddleware()})
+	g.Handle("GET", "/{id}", testController{}.Show,
+		WithNamedMiddleware(NamedMiddleware{Name: "timeout", Middleware: tagMiddleware()}))
+	g.Handle("GET", "", Adapt(nil), HandlerName("users.List"))
+
+	require.NoError(t, srv.Err())
+	routes := srv.Routes()
+	require.Len(t, routes, 4) // 包括自动注册的 OPTIONS 路由
+	assert.Equal(t, "core.testController.Show", routes[0].Handler)
+	assert.Equal(t, []string{"core.tagMiddleware", "auth", "timeout"}, routes[0].Stack)
+	assert.Equal(t, "users.List", routes[2].Handler)
+}
+
+func TestRouteConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns [][2]string
+		conflict bool
+	}{
+		{name: "不同方法", patterns: [][2]string{{"GET", "/users/{id}"}, {"PUT", "/users/{id}"}}},
+		{name: "更具体的路径", patterns: [][2]string{{"GET", "/users/{id}"}, {"GET", "/users/me"}}},
+		{name: "重复注册", patterns: [][2]string{{"GET", "/users"}, {"GET", "/users"}}, conflict: true},
+		{name: "参数名不同的相同路径", patterns: [][2]string{{"GET", "/users/{id}"}, {"GET", "/users/{name}"}}, conflict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			g := srv.Group("")
+			for _, p := range tt.patterns {
+				g.Handle(p[0], p[1], testController{}.Show)
+			}
+			if tt.conflict {
+				assert.ErrorContains(t, srv.Err(), "core.testController.Show")
+			} else {
+				assert.NoError(t, srv.Err())
+			}
+		})
+	}
+}
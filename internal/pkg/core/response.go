@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"go-api-mono/internal/pkg/errors"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // H 是一个快捷的 map[string]interface{} 类型
@@ -12,11 +14,12 @@ type H map[string]interface{}
 
 // Response 定义了统一的响应格式
 type Response struct {
-	Code    int         `json:"code"`               // 错误码
-	Message string      `json:"message"`            // 响应信息
-	Data    interface{} `json:"data,omitempty"`     // 响应数据
-	Details interface{} `json:"details,omitempty"`  // 错误详情
-	TraceID string      `json:"trace_id,omitempty"` // 请求追踪ID
+	Code      int         `json:"code"`                 // 错误码
+	Message   string      `json:"message"`              // 响应信息
+	Data      interface{} `json:"data,omitempty"`       // 响应数据
+	Details   interface{} `json:"details,omitempty"`    // 错误详情
+	TraceID   string      `json:"trace_id,omitempty"`   // 请求追踪ID
+	RequestID string      `json:"request_id,omitempty"` // 请求ID
 }
 
 // ResponseHandler 处理HTTP响应
@@ -35,6 +38,25 @@ func NewResponse(w http.ResponseWriter, r *http.Request) *ResponseHandler {
 	}
 }
 
+// traceID 返回请求所属链路的 trace id
+func traceID(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// requestID 返回请求的ID
+func requestID(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	return RequestID(r.Context())
+}
+
 // Status 设置响应状态码
 func (r *ResponseHandler) Status(code int) *ResponseHandler {
 	r.status = code
@@ -52,10 +74,9 @@ func (r *ResponseHandler) JSON(data interface{}) {
 		Data:    data,
 	}
 
-	// 获取 trace_id，如果不存在则忽略
-	if traceID, ok := r.Request.Context().Value("trace_id").(string); ok {
-		resp.TraceID = traceID
-	}
+	// 获取 trace_id 与 request_id，如果不存在则忽略
+	resp.TraceID = traceID(r.Request)
+	resp.RequestID = requestID(r.Request)
 
 	if err := json.NewEncoder(r.Writer).Encode(resp); err != nil {
 		http.Error(r.Writer, err.Error(), http.StatusInternalServerError)
@@ -82,10 +103,9 @@ func (r *ResponseHandler) Error(err error) {
 		}
 	}
 
-	// 获取 trace_id，如果不存在则忽略
-	if traceID, ok := r.Request.Context().Value("trace_id").(string); ok {
-		resp.TraceID = traceID
-	}
+	// 获取 trace_id 与 request_id，如果不存在则忽略
+	resp.TraceID = traceID(r.Request)
+	resp.RequestID = requestID(r.Request)
 
 	if err := json.NewEncoder(r.Writer).Encode(resp); err != nil {
 		http.Error(r.Writer, err.Error(), http.StatusInternalServerError)
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+)
+
+// routeKey 当前路由在上下文中的键
+type routeKey struct{}
+
+// Route 描述一条已注册的路由
+type Route struct {
+	Method      string            // 请求方法
+	Path        string            // 完整路径模式
+	Version     *Version          // 所属API版本
+	Deprecation *Deprecation      // 路由级弃用信息，优先于版本级
+	Middlewares []NamedMiddleware // 路由级中间件
+	Handler     string            // 处理器名称
+	Stack       []string          // 中间件名称，由外到内，包括全局、路由组与路由级中间件
+
+	// 文档元数据
+	Summary     string               // 摘要
+	Description string               // 详细说明
+	Tags        []string             // 分组标签
+	Request     reflect.Type         // 请求体类型
+	Responses   map[int]reflect.Type // 状态码 -> 响应数据类型，nil 表示无响应数据
+	Params      []Param              // 路径与查询参数
+	Security    []string             // 需要的安全方案
+	Hidden      bool                 // 是否从文档中隐藏
+	Automatic   bool                 // 是否由路由器自动注册，如 OPTIONS 路由
+}
+
+// Param 描述一个路径或查询参数
+type Param struct {
+	Name        string // 参数名
+	In          string // 参数位置：path、query 或 header
+	Type        string // JSON Schema 类型
+	Description string // 参数说明
+	Required    bool   // 是否必填
+}
+
+// pathParamPattern 匹配路由模式中的路径参数
+var pathParamPattern = regexp.MustCompile(`\{([^}.]+)(?:\.\.\.)?\}`)
+
+// PathParams 返回路由模式中的路径参数名
+func (r *Route) PathParams() []string {
+	var names []string
+	for _, m := range pathParamPattern.FindAllStringSubmatch(r.Path, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// IsDeprecated 判断路由是否已弃用
+func (r *Route) IsDeprecated() bool {
+	return r.Deprecation != nil || (r.Version != nil && r.Version.Deprecation != nil)
+}
+
+// RouteOption 定义路由选项
+type RouteOption func(*Route)
+
+// automatic 标记路由由路由器自动注册
+func automatic() RouteOption {
+	return func(r *Route) {
+		r.Automatic = true
+	}
+}
+
+// WithMiddleware 为单条路由添加中间件，在路由组中间件之后执行，路由表中以函数名显示
+func WithMiddleware(middlewares ...Middleware) RouteOption {
+	return WithNamedMiddleware(named(middlewares)...)
+}
+
+// WithNamedMiddleware 为单条路由添加带名称的中间件
+func WithNamedMiddleware(middlewares ...NamedMiddleware) RouteOption {
+	return func(r *Route) {
+		r.Middlewares = append(r.Middlewares, middlewares...)
+	}
+}
+
+// Summary 设置路由摘要与说明
+func Summary(summary string, description ...string) RouteOption {
+	return func(r *Route) {
+		r.Summary = summary
+		if len(description) > 0 {
+			r.Description = description[0]
+		}
+	}
+}
+
+// Tags 设置路由分组标签
+func Tags(tags ...string) RouteOption {
+	return func(r *Route) {
+		r.Tags = append(r.Tags, tags...)
+	}
+}
+
+// RequestBody 设置请求体类型，参数为该类型的零值，如 model.LoginRequest{}
+func RequestBody(body interface{}) RouteOption {
+	return func(r *Route) {
+		r.Request = reflect.TypeOf(body)
+	}
+}
+
+// ResponseBody 设置指定状态码的响应数据类型，body 为 nil 表示无响应数据
+func ResponseBody(status int, body interface{}) RouteOption {
+	return func(r *Route) {
+		if r.Responses == nil {
+			r.Responses = make(map[int]reflect.Type)
+		}
+		r.Responses[status] = reflect.TypeOf(body)
+	}
+}
+
+// PathParam 描述路径参数
+func PathParam(name, typ, description string) RouteOption {
+	return func(r *Route) {
+		r.Params = append(r.Params, Param{Name: name, In: "path", Type: typ, Description: description, Required: true})
+	}
+}
+
+// QueryParam 描述查询参数
+func QueryParam(name, typ, description string) RouteOption {
+	return func(r *Route) {
+		r.Params = append(r.Params, Param{Name: name, In: "query", Type: typ, Description: description})
+	}
+}
+
+// HeaderParam 描述请求头参数
+func HeaderParam(name, typ, description string) RouteOption {
+	return func(r *Route) {
+		r.Params = append(r.Params, Param{Name: name, In: "header", Type: typ, Description: description})
+	}
+}
+
+// Security 设置路由需要的安全方案
+func Security(schemes ...string) RouteOption {
+	return func(r *Route) {
+		r.Security = append(r.Security, schemes...)
+	}
+}
+
+// HandlerName 设置路由表中显示的处理器名称，用于名称无法从函数推断的处理器，如 Adapt 适配的处理器
+func HandlerName(name string) RouteOption {
+	return func(r *Route) {
+		r.Handler = name
+	}
+}
+
+// Hidden 将路由从文档中隐藏
+func Hidden() RouteOption {
+	return func(r *Route) {
+		r.Hidden = true
+	}
+}
+
+// withRoute 将当前路由注入请求上下文
+func withRoute(route *Route, next HandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		c.SetRequest(c.Request.WithContext(context.WithValue(c.Request.Context(), routeKey{}, route)))
+		next(c)
+	}
+}
+
+// CurrentRoute 从上下文中获取当前请求匹配的路由
+func CurrentRoute(ctx context.Context) *Route {
+	route, _ := ctx.Value(routeKey{}).(*Route)
+	return route
+}
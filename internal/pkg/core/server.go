@@ -3,10 +3,15 @@ package core
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"go-api-mono/internal/pkg/errors"
 	"go-api-mono/internal/pkg/logger"
 )
 
@@ -18,7 +23,10 @@ type ServerOptions struct {
 	Port         int
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
 	Logger       *logger.Logger
+	// TrustedProxies 受信任的反向代理网段，只有来自这些地址的转发头才会用于解析客户端IP
+	TrustedProxies []netip.Prefix
 }
 
 // Server 封装HTTP服务器
@@ -26,7 +34,10 @@ type Server struct {
 	server      *http.Server
 	logger      *logger.Logger
 	mux         *http.ServeMux
-	middlewares []Middleware
+	middlewares []NamedMiddleware
+	routes      []*Route
+	methods     map[string][]string // 路径模式 -> 已注册的请求方法
+	errs        []error             // 注册路由时发现的冲突
 }
 
 // Group 路由组
@@ -34,28 +45,100 @@ type Group struct {
 	prefix      string
 	server      *Server
 	parent      *Group
-	middlewares []Middleware
+	middlewares []NamedMiddleware
+	api         *API     // 所属的版本化API，未启用版本时为nil
+	version     *Version // 路由组对应的API版本
 }
 
 // NewServer 创建一个新的服务器实例
 func NewServer(opts ServerOptions) *Server {
-	mux := http.NewServeMux()
 	srv := &Server{
 		server: &http.Server{
 			Addr:         fmt.Sprintf(":%d", opts.Port),
-			Handler:      mux,
 			ReadTimeout:  opts.ReadTimeout,
 			WriteTimeout: opts.WriteTimeout,
+			IdleTimeout:  opts.IdleTimeout,
 		},
 		logger:      opts.Logger,
-		mux:         mux,
-		middlewares: make([]Middleware, 0),
+		mux:         http.NewServeMux(),
+		middlewares: make([]NamedMiddleware, 0),
+		methods:     make(map[string][]string),
 	}
+	srv.server.Handler = NewClientIPResolver(opts.TrustedProxies).Handler(http.HandlerFunc(srv.serveHTTP))
 	return srv
 }
 
-// Use 添加全局中间件
+// serveHTTP 分发请求，未匹配任何路由的请求经过全局中间件后以统一的错误响应应答
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := s.mux.Handler(r); pattern != "" {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	WrapHandler(s.withGlobal(s.unmatched), s.logger)(w, r)
+}
+
+// withGlobal 使用全局中间件包装不属于任何路由的处理器
+func (s *Server) withGlobal(handler HandlerFunc) HandlerFunc {
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		handler = s.middlewares[i].Middleware(handler)
+	}
+	return handler
+}
+
+// unmatched 路径不存在时返回 404，路径存在但不支持请求方法时返回 405 并在 Allow 头中列出支持的方法
+func (s *Server) unmatched(c *Context) {
+	allowed := s.allowedMethods(c.Request)
+	if len(allowed) == 0 {
+		c.Response.Error(errors.ErrNotFound)
+		return
+	}
+	c.Response.Writer.Header().Set("Allow", strings.Join(allowed, ", "))
+	c.Response.Error(errors.New(errors.ErrCodeMethodNotAllowed, "Method not allowed").
+		WithDetails(map[string]interface{}{"allowed": allowed}))
+}
+
+// allowedMethods 返回请求路径支持的方法，逐一尝试已注册的方法
+func (s *Server) allowedMethods(r *http.Request) []string {
+	seen := make(map[string]bool)
+	for _, methods := range s.methods {
+		for _, method := range methods {
+			seen[method] = true
+		}
+	}
+
+	var candidates []string
+	for method := range seen {
+		candidates = append(candidates, method)
+	}
+	var allowed []string
+	for _, method := range withHead(candidates) {
+		probe := *r
+		probe.Method = method
+		if _, pattern := s.mux.Handler(&probe); pattern != "" {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
+
+// withHead 返回排序后的方法列表，支持 GET 时同时支持 HEAD
+func withHead(methods []string) []string {
+	methods = append([]string(nil), methods...)
+	if slices.Contains(methods, http.MethodGet) && !slices.Contains(methods, http.MethodHead) {
+		methods = append(methods, http.MethodHead)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// Use 添加全局中间件，路由表中以函数名显示
 func (s *Server) Use(middlewares ...Middleware) {
+	s.UseNamed(named(middlewares)...)
+}
+
+// UseNamed 添加带名称的全局中间件
+func (s *Server) UseNamed(middlewares ...NamedMiddleware) {
 	s.middlewares = append(s.middlewares, middlewares...)
 }
 
@@ -64,7 +147,7 @@ func (s *Server) Group(prefix string) *Group {
 	return &Group{
 		prefix:      prefix,
 		server:      s,
-		middlewares: make([]Middleware, 0),
+		middlewares: make([]NamedMiddleware, 0),
 	}
 }
 
@@ -74,47 +157,109 @@ func (g *Group) Group(prefix string) *Group {
 		prefix:      g.prefix + prefix,
 		server:      g.server,
 		parent:      g,
-		middlewares: make([]Middleware, 0),
+		middlewares: make([]NamedMiddleware, 0),
+		api:         g.api,
+		version:     g.version,
 	}
 }
 
-// Use 为路由组添加中间件
+// Prefix 返回路由组的完整路径前缀
+func (g *Group) Prefix() string {
+	return joinPath(g.prefix, "")
+}
+
+// Use 为路由组添加中间件，路由表中以函数名显示
 func (g *Group) Use(middlewares ...Middleware) {
+	g.UseNamed(named(middlewares)...)
+}
+
+// UseNamed 为路由组添加带名称的中间件
+func (g *Group) UseNamed(middlewares ...NamedMiddleware) {
 	g.middlewares = append(g.middlewares, middlewares...)
 }
 
 // Handle 注册路由处理器
-func (g *Group) Handle(method, pattern string, handler HandlerFunc) {
-	// 收集所有中间件
-	var allMiddlewares []Middleware
+func (g *Group) Handle(method, pattern string, handler HandlerFunc, opts ...RouteOption) {
+	route := &Route{
+		Method:  method,
+		Path:    joinPath(g.prefix, pattern),
+		Version: g.version,
+		Handler: FuncName(handler),
+	}
+	for _, opt := range opts {
+		opt(route)
+	}
+
+	// 收集所有中间件：全局、由外到内的各级路由组、路由级
+	var allMiddlewares []NamedMiddleware
 	allMiddlewares = append(allMiddlewares, g.server.middlewares...)
 
-	// 收集父组的中间件
-	parent := g
-	for parent != nil {
-		allMiddlewares = append(allMiddlewares, parent.middlewares...)
-		parent = parent.parent
+	var groups []*Group
+	for parent := g; parent != nil; parent = parent.parent {
+		groups = append(groups, parent)
 	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		allMiddlewares = append(allMiddlewares, groups[i].middlewares...)
+	}
+	allMiddlewares = append(allMiddlewares, route.Middlewares...)
 
 	// 创建中间件链
 	finalHandler := handler
 	for i := len(allMiddlewares) - 1; i >= 0; i-- {
-		finalHandler = allMiddlewares[i](finalHandler)
+		finalHandler = allMiddlewares[i].Middleware(finalHandler)
+	}
+	for _, m := range allMiddlewares {
+		route.Stack = append(route.Stack, m.Name)
 	}
 
-	// 移除前导和尾部斜杠以避免重定向
-	pattern = strings.Trim(pattern, "/")
-	prefix := strings.Trim(g.prefix, "/")
+	// 版本与弃用响应头位于最外层，使中间件返回的错误同样携带这些信息
+	if route.Version != nil {
+		finalHandler = versionHeaders(route, finalHandler)
+	}
+	finalHandler = withRoute(route, finalHandler)
+
+	if !g.server.register(fmt.Sprintf("%s %s", method, route.Path), finalHandler, route) {
+		return
+	}
+	g.server.routes = append(g.server.routes, route)
+
+	if g.api != nil && route.Version != nil {
+		g.api.bind(route, finalHandler)
+	}
+
+	// 首次注册某个路径时，自动注册经过相同路由组中间件的 OPTIONS 路由，
+	// 使跨域预检等 OPTIONS 请求能够到达路由组的中间件
+	methods := g.server.methods[route.Path]
+	g.server.methods[route.Path] = append(methods, method)
+	if len(methods) == 0 && method != http.MethodOptions {
+		g.Handle(http.MethodOptions, pattern, g.server.allowHandler, Hidden(), automatic())
+	}
+}
 
-	// 构建完整的路由模式
-	var fullPattern string
-	if pattern == "" {
-		fullPattern = fmt.Sprintf("%s %s", method, "/"+prefix)
-	} else {
-		fullPattern = fmt.Sprintf("%s %s", method, "/"+prefix+"/"+pattern)
+// allowHandler 应答自动注册的 OPTIONS 请求，在 Allow 头中列出路径支持的方法
+// GET 路由同时应答 HEAD 请求，响应体由 net/http 丢弃
+func (s *Server) allowHandler(c *Context) {
+	route := CurrentRoute(c.Request.Context())
+	if route != nil {
+		c.Response.Writer.Header().Set("Allow", strings.Join(withHead(s.methods[route.Path]), ", "))
 	}
+	c.Response.NoContent()
+}
+
+// joinPath 拼接路由组前缀与路由模式
+// 移除前导和尾部斜杠以避免重定向
+func joinPath(prefix, pattern string) string {
+	pattern = strings.Trim(pattern, "/")
+	prefix = strings.Trim(prefix, "/")
 
-	g.server.mux.Handle(fullPattern, WrapHandler(finalHandler, g.server.logger))
+	switch {
+	case pattern == "":
+		return "/" + prefix
+	case prefix == "":
+		return "/" + pattern
+	default:
+		return "/" + prefix + "/" + pattern
+	}
 }
 
 // HandleFunc 注册路由处理器
@@ -122,10 +267,34 @@ func (s *Server) HandleFunc(pattern string, handler HandlerFunc) {
 	s.mux.Handle(pattern, WrapHandler(handler, s.logger))
 }
 
+// Routes 返回已注册的路由
+func (s *Server) Routes() []*Route {
+	return s.routes
+}
+
+// Handler 返回服务器的根处理器
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
 // Start 启动服务器
 func (s *Server) Start() error {
-	s.logger.Info(fmt.Sprintf("Server is starting on %s", s.server.Addr))
-	return s.server.ListenAndServe()
+	ln, err := s.Listen()
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Listen 监听服务器地址，监听成功后即可接受连接，请求在调用 Serve 后开始处理
+func (s *Server) Listen() (net.Listener, error) {
+	return net.Listen("tcp", s.server.Addr)
+}
+
+// Serve 在监听器上处理请求，直到服务器关闭
+func (s *Server) Serve(ln net.Listener) error {
+	s.logger.Info(fmt.Sprintf("Server is starting on %s", ln.Addr()))
+	return s.server.Serve(ln)
 }
 
 // Stop 优雅关闭服务器
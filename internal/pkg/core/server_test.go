@@ -0,0 +1,104 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-mono/internal/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomaticOptions(t *testing.T) {
+	srv := newTestServer(t)
+
+	var seen []string
+	g := srv.Group("/users")
+	g.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			seen = append(seen, c.Request.Method)
+			next(c)
+		}
+	})
+	ok := func(c *Context) { c.Response.Success(nil) }
+	g.Handle("GET", "/{id}", ok)
+	g.Handle("PUT", "/{id}", ok)
+	g.Handle("DELETE", "/{id}", ok)
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/users/1", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "DELETE, GET, HEAD, OPTIONS, PUT", w.Header().Get("Allow"))
+	// OPTIONS 请求经过路由组中间件
+	assert.Equal(t, []string{http.MethodOptions}, seen)
+
+	// 未注册的路径不应答 OPTIONS
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 自动注册的路由不出现在文档中
+	var automatic int
+	for _, route := range srv.Routes() {
+		if route.Automatic {
+			automatic++
+			assert.True(t, route.Hidden)
+		}
+	}
+	assert.Equal(t, 1, automatic)
+}
+
+func TestUnmatchedRequests(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			c.SetRequest(c.Request.WithContext(WithRequestID(c.Request.Context(), "req-1")))
+			next(c)
+		}
+	})
+	ok := func(c *Context) { c.Response.Success("ok") }
+	srv.Group("/users").Handle("GET", "/{id}", ok)
+	srv.Group("/users").Handle("PUT", "/{id}", ok)
+	v1 := srv.API("/api", VersioningOptions{Default: "v1"}).Version("v1")
+	v1.Handle("POST", "/orders", ok)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantCode   errors.ErrorCode
+		wantAllow  string
+	}{
+		{name: "未知路径", method: http.MethodGet, path: "/unknown", wantStatus: http.StatusNotFound, wantCode: errors.ErrCodeNotFound},
+		{name: "不支持的方法", method: http.MethodPost, path: "/users/1", wantStatus: http.StatusMethodNotAllowed, wantCode: errors.ErrCodeMethodNotAllowed, wantAllow: "GET, HEAD, OPTIONS, PUT"},
+		{name: "版本化路径", method: http.MethodGet, path: "/api/v1/orders", wantStatus: http.StatusMethodNotAllowed, wantCode: errors.ErrCodeMethodNotAllowed, wantAllow: "OPTIONS, POST"},
+		{name: "无版本路径", method: http.MethodDelete, path: "/api/orders", wantStatus: http.StatusMethodNotAllowed, wantCode: errors.ErrCodeMethodNotAllowed, wantAllow: "OPTIONS, POST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantAllow, w.Header().Get("Allow"))
+			var body Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, int(tt.wantCode), body.Code)
+			// 未匹配的请求同样经过全局中间件
+			assert.Equal(t, "req-1", body.RequestID)
+		})
+	}
+
+	t.Run("GET 路由应答 HEAD", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/users/1", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -0,0 +1,256 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-mono/internal/pkg/errors"
+)
+
+// versionKey API版本在上下文中的键
+type versionKey struct{}
+
+// VersioningOptions 定义版本协商选项
+type VersioningOptions struct {
+	Default   string // 请求未指定版本时使用的版本
+	Header    string // 指定版本的请求头，默认 API-Version
+	MediaType string // Accept 中携带 version 参数的媒体类型，为空时不限制媒体类型
+}
+
+// Deprecation 描述弃用信息
+type Deprecation struct {
+	Since  time.Time // 弃用生效时间，为零值时仅标记为已弃用
+	Sunset time.Time // 计划下线时间
+	Link   string    // 迁移说明文档地址
+}
+
+// Version 描述一个API版本
+type Version struct {
+	Name        string       // 版本名称，如 v1
+	Deprecation *Deprecation // 版本级弃用信息，为nil表示未弃用
+}
+
+// VersionOption 定义版本选项
+type VersionOption func(*Version)
+
+// Deprecated 将版本标记为已弃用
+func Deprecated(d Deprecation) VersionOption {
+	return func(v *Version) {
+		v.Deprecation = &d
+	}
+}
+
+// DeprecatedRoute 将单条路由标记为已弃用
+func DeprecatedRoute(d Deprecation) RouteOption {
+	return func(r *Route) {
+		r.Deprecation = &d
+	}
+}
+
+// API 版本化的API路由
+// 每个版本的路由同时注册在带版本的路径（如 /api/v1/users）和不带版本的路径
+// （如 /api/users）下，后者根据 API-Version 请求头或 Accept 媒体类型参数选择版本
+type API struct {
+	prefix   string
+	server   *Server
+	opts     VersioningOptions
+	versions map[string]*Version
+
+	mu     sync.RWMutex
+	routes map[string]map[string]HandlerFunc // 无版本路由模式 -> 版本 -> 处理器
+}
+
+// API 创建版本化的API路由
+func (s *Server) API(prefix string, opts VersioningOptions) *API {
+	if opts.Header == "" {
+		opts.Header = "API-Version"
+	}
+	opts.Default = normalizeVersion(opts.Default)
+	return &API{
+		prefix:   prefix,
+		server:   s,
+		opts:     opts,
+		versions: make(map[string]*Version),
+		routes:   make(map[string]map[string]HandlerFunc),
+	}
+}
+
+// Version 创建指定版本的路由组
+func (a *API) Version(name string, opts ...VersionOption) *Group {
+	version := &Version{Name: normalizeVersion(name)}
+	for _, opt := range opts {
+		opt(version)
+	}
+	a.versions[version.Name] = version
+
+	return &Group{
+		prefix:      joinPath(a.prefix, version.Name),
+		server:      a.server,
+		middlewares: make([]NamedMiddleware, 0),
+		api:         a,
+		version:     version,
+	}
+}
+
+// bind 将版本路由绑定到不带版本的路径上
+func (a *API) bind(route *Route, handler HandlerFunc) {
+	versionPrefix := joinPath(a.prefix, route.Version.Name)
+	path := joinPath(a.prefix, strings.TrimPrefix(route.Path, versionPrefix))
+	pattern := fmt.Sprintf("%s %s", route.Method, path)
+
+	a.mu.Lock()
+	handlers, exists := a.routes[pattern]
+	if !exists {
+		handlers = make(map[string]HandlerFunc)
+		a.routes[pattern] = handlers
+	}
+	handlers[route.Version.Name] = handler
+	a.mu.Unlock()
+
+	if !exists {
+		a.server.register(pattern, a.dispatch(pattern), route)
+	}
+}
+
+// dispatch 根据协商出的版本分发请求
+// 协商失败或版本中不存在该路由时，与未匹配的请求一样只经过全局中间件，不执行路由组与路由的认证、
+// 幂等等中间件；错误响应携带 API-Version 响应头：版本不受支持时为默认版本，否则为协商出的版本
+func (a *API) dispatch(pattern string) HandlerFunc {
+	fail := func(c *Context, version string, err error) {
+		withVersion(version, a.server.withGlobal(func(c *Context) {
+			c.Response.Error(err)
+		}))(c)
+	}
+
+	return func(c *Context) {
+		version, err := a.negotiate(c.Request)
+		if err != nil {
+			fail(c, a.opts.Default, err)
+			return
+		}
+
+		a.mu.RLock()
+		handler, ok := a.routes[pattern][version]
+		a.mu.RUnlock()
+		if !ok {
+			fail(c, version, errors.New(errors.ErrCodeNotFound, "route not available in this API version").
+				WithDetails(map[string]interface{}{"version": version}))
+			return
+		}
+		handler(c)
+	}
+}
+
+// negotiate 从请求头或 Accept 媒体类型参数中确定API版本
+func (a *API) negotiate(r *http.Request) (string, error) {
+	requested := r.Header.Get(a.opts.Header)
+	if requested == "" {
+		requested = a.acceptVersion(r.Header.Get("Accept"))
+	}
+	if requested == "" {
+		requested = a.opts.Default
+	}
+
+	version := normalizeVersion(requested)
+	if _, ok := a.versions[version]; !ok {
+		return "", errors.New(errors.ErrCodeBadRequest, "unsupported API version").
+			WithDetails(map[string]interface{}{
+				"version":   requested,
+				"supported": a.Versions(),
+			})
+	}
+	return version, nil
+}
+
+// acceptVersion 解析 Accept 头中的 version 参数
+func (a *API) acceptVersion(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if a.opts.MediaType != "" && mediaType != a.opts.MediaType {
+			continue
+		}
+		if v := params["version"]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Versions 返回已注册的版本列表
+func (a *API) Versions() []string {
+	versions := make([]string, 0, len(a.versions))
+	for name := range a.versions {
+		versions = append(versions, name)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// versionHeaders 注入版本上下文并写出版本与弃用相关的响应头
+func versionHeaders(route *Route, next HandlerFunc) HandlerFunc {
+	deprecation := route.Deprecation
+	if deprecation == nil {
+		deprecation = route.Version.Deprecation
+	}
+
+	return withVersion(route.Version.Name, func(c *Context) {
+		if deprecation != nil {
+			writeDeprecationHeaders(c.Response.Writer.Header(), deprecation)
+		}
+		next(c)
+	})
+}
+
+// withVersion 注入版本上下文并写出 API-Version 响应头，version 为空时不处理
+func withVersion(version string, next HandlerFunc) HandlerFunc {
+	if version == "" {
+		return next
+	}
+	return func(c *Context) {
+		c.Response.Writer.Header().Set("API-Version", version)
+		c.SetRequest(c.Request.WithContext(context.WithValue(c.Request.Context(), versionKey{}, version)))
+		next(c)
+	}
+}
+
+// writeDeprecationHeaders 写出 Deprecation（RFC 9745）、Sunset（RFC 8594）与 Link 响应头
+func writeDeprecationHeaders(header http.Header, d *Deprecation) {
+	if d.Since.IsZero() {
+		header.Set("Deprecation", "true")
+	} else {
+		header.Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+	}
+	if !d.Sunset.IsZero() {
+		header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		header.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"; type="text/html"`, d.Link))
+		if !d.Sunset.IsZero() {
+			header.Add("Link", fmt.Sprintf(`<%s>; rel="sunset"; type="text/html"`, d.Link))
+		}
+	}
+}
+
+// normalizeVersion 统一版本名称格式，如 "2" -> "v2"
+func normalizeVersion(v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if v != "" && !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	return v
+}
+
+// APIVersion 从上下文中获取当前请求的API版本
+func APIVersion(ctx context.Context) string {
+	v, _ := ctx.Value(versionKey{}).(string)
+	return v
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-api-mono/internal/pkg/errors"
+	"go-api-mono/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) *Server {
+	log, err := logger.New(logger.LogConfig{
+		Level:    "debug",
+		Filename: filepath.Join(t.TempDir(), "test.log"),
+	})
+	assert.NoError(t, err)
+	return NewServer(ServerOptions{Logger: log})
+}
+
+func TestAPIVersioning(t *testing.T) {
+	srv := newTestServer(t)
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	api := srv.API("/api", VersioningOptions{Default: "v1", MediaType: "application/vnd.test+json"})
+	// 记录请求经过全局中间件时的路由与版本，版本协商失败的请求同样经过
+	srv.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			if route := CurrentRoute(c.Request.Context()); route != nil {
+				c.Response.Writer.Header().Set("X-Route", route.Path)
+			}
+			c.Response.Writer.Header().Set("X-Version", APIVersion(c.Request.Context()))
+			next(c)
+		}
+	})
+
+	v1 := api.Version("v1", Deprecated(Deprecation{
+		Since:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset: sunset,
+		Link:   "https://example.com/migrate",
+	}))
+	// 路由组中间件（如认证）不处理版本协商失败的请求
+	v1.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			if c.Request.Header.Get("X-Reject") != "" {
+				c.Response.Error(errors.ErrUnauthorized)
+				return
+			}
+			next(c)
+		}
+	})
+	v1.Handle("GET", "/users", func(c *Context) { c.Response.Success("v1") })
+
+	v2 := api.Version("v2")
+	v2.Handle("GET", "/users", func(c *Context) { c.Response.Success(APIVersion(c.Request.Context())) })
+	v2.Handle("GET", "/legacy", func(c *Context) { c.Response.Success("legacy") },
+		DeprecatedRoute(Deprecation{}))
+
+	tests := []struct {
+		name            string
+		path            string
+		header          map[string]string
+		expectedStatus  int
+		expectedVersion string
+		deprecated      bool
+	}{
+		{name: "路径前缀", path: "/api/v1/users", expectedStatus: http.StatusOK, expectedVersion: "v1", deprecated: true},
+		{name: "默认版本", path: "/api/users", expectedStatus: http.StatusOK, expectedVersion: "v1", deprecated: true},
+		{name: "版本请求头", path: "/api/users", header: map[string]string{"API-Version": "2"}, expectedStatus: http.StatusOK, expectedVersion: "v2"},
+		{name: "Accept参数", path: "/api/users", header: map[string]string{"Accept": "application/vnd.test+json; version=2"}, expectedStatus: http.StatusOK, expectedVersion: "v2"},
+		{name: "不支持的版本", path: "/api/users", header: map[string]string{"API-Version": "9"}, expectedStatus: http.StatusBadRequest, expectedVersion: "v1"},
+		{name: "不支持的版本不经过路由组中间件", path: "/api/users", header: map[string]string{"API-Version": "9", "X-Reject": "1"}, expectedStatus: http.StatusBadRequest, expectedVersion: "v1"},
+		{name: "路由组中间件", path: "/api/users", header: map[string]string{"X-Reject": "1"}, expectedStatus: http.StatusUnauthorized, expectedVersion: "v1", deprecated: true},
+		{name: "路由级弃用", path: "/api/v2/legacy", expectedStatus: http.StatusOK, expectedVersion: "v2", deprecated: true},
+		{name: "版本中不存在的路由", path: "/api/legacy", expectedStatus: http.StatusNotFound, expectedVersion: "v1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedVersion, w.Header().Get("API-Version"))
+			assert.Equal(t, tt.expectedVersion, w.Header().Get("X-Version"))
+			// 版本协商失败的请求不属于任何路由
+			assert.Equal(t, tt.expectedStatus != http.StatusBadRequest && tt.expectedStatus != http.StatusNotFound, w.Header().Get("X-Route") != "")
+			assert.Equal(t, tt.deprecated, w.Header().Get("Deprecation") != "")
+		})
+	}
+
+	// 版本级弃用同时输出 Sunset 与 Link
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+	assert.Equal(t, sunset.Format(http.TimeFormat), w.Header().Get("Sunset"))
+	assert.Contains(t, w.Header().Values("Link"), `<https://example.com/migrate>; rel="deprecation"; type="text/html"`)
+}
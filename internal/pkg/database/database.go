@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -51,6 +52,11 @@ func New(config DatabaseConfig) (*DB, error) {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
 	}
 
+	// 为SQL创建链路追踪span
+	if err := db.Use(tracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
 	// 设置连接池
 	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
@@ -68,6 +74,20 @@ func (db *DB) Close() error {
 	return sqlDB.Close()
 }
 
+// Ping 检查数据库连接是否可用
+func (db *DB) Ping(ctx context.Context) error {
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database instance: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Check 实现健康检查接口
+func (db *DB) Check(ctx context.Context) error {
+	return db.Ping(ctx)
+}
+
 // AutoMigrate 自动迁移数据库表
 func (db *DB) AutoMigrate(models ...interface{}) error {
 	return db.DB.AutoMigrate(models...)
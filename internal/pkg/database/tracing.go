@@ -0,0 +1,90 @@
+package database
+
+import (
+	"errors"
+
+	"go-api-mono/internal/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey span 在 gorm 实例中的存储键
+const spanKey = "otel:span"
+
+// tracingPlugin 为每条 SQL 创建子 span 的 gorm 插件
+// 只有通过 WithContext 传入请求上下文的查询才会挂到请求的链路上
+type tracingPlugin struct{}
+
+// Name 实现 gorm.Plugin 接口
+func (tracingPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize 实现 gorm.Plugin 接口，在各类操作前后注册回调
+func (p tracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		name   string
+		before func(string, func(*gorm.DB)) error
+		after  func(string, func(*gorm.DB)) error
+	}{
+		{"gorm.Create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"gorm.Query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"gorm.Update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"gorm.Delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"gorm.Row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"gorm.Raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, h := range hooks {
+		if err := h.before("tracing:before_"+h.name, p.before(h.name)); err != nil {
+			return err
+		}
+		if err := h.after("tracing:after_"+h.name, p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before 开始 span 并将其保存到 gorm 实例中
+func (tracingPlugin) before(name string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+		_, span := tracing.Start(db.Statement.Context, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemMySQL),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// after 记录语句、影响行数与错误并结束 span
+func (tracingPlugin) after(db *gorm.DB) {
+	v, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		semconv.DBCollectionName(db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	// 记录不存在属于正常的业务结果，不标记为错误
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
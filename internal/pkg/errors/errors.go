@@ -43,6 +43,14 @@ const (
 	ErrCodeResourceUnavailable
 )
 
+// 扩展的请求相关错误码
+// 使用显式取值，避免影响上面按 iota 推导出的既有错误码
+const (
+	ErrCodeRequestEntityTooLarge ErrorCode = 120 + iota
+	ErrCodeTimeout
+	ErrCodeServiceUnavailable
+)
+
 // Error 定义自定义错误类型
 type Error struct {
 	Code     ErrorCode   `json:"code"`              // 错误码
@@ -173,6 +181,10 @@ func errorCodeToHTTPStatus(code ErrorCode) int {
 			return http.StatusTooManyRequests
 		case ErrCodeValidation:
 			return http.StatusUnprocessableEntity
+		case ErrCodeRequestEntityTooLarge:
+			return http.StatusRequestEntityTooLarge
+		case ErrCodeTimeout, ErrCodeServiceUnavailable:
+			return http.StatusServiceUnavailable
 		default:
 			return http.StatusBadRequest
 		}
@@ -204,6 +216,9 @@ var (
 	ErrTooManyRequests  = New(ErrCodeTooManyRequests, "Too many requests")
 	ErrValidation       = New(ErrCodeValidation, "Validation failed")
 
+	ErrRequestEntityTooLarge = New(ErrCodeRequestEntityTooLarge, "Request entity too large")
+	ErrTimeout               = New(ErrCodeTimeout, "Request timeout")
+
 	// 认证错误
 	ErrInvalidToken       = New(ErrCodeInvalidToken, "Invalid token")
 	ErrTokenExpired       = New(ErrCodeTokenExpired, "Token expired")
@@ -0,0 +1,38 @@
+package health
+
+import (
+	"context"
+	"net/http"
+
+	"go-api-mono/internal/pkg/core"
+)
+
+// DetailFunc 判断请求方是否可以查看检查明细
+type DetailFunc func(r *http.Request) bool
+
+// LivenessHandler 存活检查处理器
+func LivenessHandler(reg *Registry, detailed DetailFunc) core.HandlerFunc {
+	return handler(reg.Liveness, detailed)
+}
+
+// ReadinessHandler 就绪检查处理器
+func ReadinessHandler(reg *Registry, detailed DetailFunc) core.HandlerFunc {
+	return handler(reg.Readiness, detailed)
+}
+
+// handler 执行检查并输出报告，仅向有权限的请求方返回检查明细
+func handler(run func(context.Context) Report, detailed DetailFunc) core.HandlerFunc {
+	return func(c *core.Context) {
+		report := run(c.Request.Context())
+		if detailed == nil || !detailed(c.Request) {
+			report.Checks = nil
+		}
+
+		status := http.StatusOK
+		if report.Status != StatusUp {
+			status = http.StatusServiceUnavailable
+		}
+		c.Response.Writer.Header().Set("Cache-Control", "no-store")
+		c.Response.Status(status).JSON(report)
+	}
+}
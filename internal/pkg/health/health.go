@@ -0,0 +1,205 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status 健康状态
+type Status string
+
+const (
+	// StatusUp 健康
+	StatusUp Status = "up"
+	// StatusDown 不健康
+	StatusDown Status = "down"
+)
+
+// ErrShuttingDown 服务正在关闭
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Checker 健康检查器接口
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc 将函数适配为 Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check 实现 Checker 接口
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckOptions 检查项选项
+type CheckOptions struct {
+	Timeout  time.Duration // 单次检查超时时间，为0时使用注册表默认值
+	Liveness bool          // 是否同时参与存活检查，默认仅参与就绪检查
+	Optional bool          // 非关键检查，失败只体现在明细中而不影响整体状态
+}
+
+// Result 单个检查项的结果
+type Result struct {
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Optional  bool      `json:"optional,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report 健康检查报告
+type Report struct {
+	Status Status   `json:"status"`
+	Checks []Result `json:"checks,omitempty"`
+}
+
+// check 已注册的检查项
+type check struct {
+	name    string
+	checker Checker
+	opts    CheckOptions
+
+	mu     sync.Mutex
+	result Result
+}
+
+// Registry 健康检查注册表
+type Registry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu           sync.RWMutex
+	checks       []*check
+	shuttingDown atomic.Bool
+	now          func() time.Time
+}
+
+// NewRegistry 创建健康检查注册表
+// timeout 为默认的单次检查超时时间，cacheTTL 为结果缓存时间
+func NewRegistry(timeout, cacheTTL time.Duration) *Registry {
+	return &Registry{
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+	}
+}
+
+// Register 注册检查项
+func (r *Registry) Register(name string, checker Checker, opts CheckOptions) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = r.timeout
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, &check{name: name, checker: checker, opts: opts})
+}
+
+// MarkShuttingDown 标记服务正在关闭，此后就绪检查始终失败
+func (r *Registry) MarkShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// ShuttingDown 判断服务是否正在关闭
+func (r *Registry) ShuttingDown() bool {
+	return r.shuttingDown.Load()
+}
+
+// Liveness 执行存活检查
+func (r *Registry) Liveness(ctx context.Context) Report {
+	return r.run(ctx, func(c *check) bool { return c.opts.Liveness })
+}
+
+// Readiness 执行就绪检查
+func (r *Registry) Readiness(ctx context.Context) Report {
+	report := r.run(ctx, func(*check) bool { return true })
+	if r.ShuttingDown() {
+		report.Status = StatusDown
+		report.Checks = append([]Result{{
+			Name:      "shutdown",
+			Status:    StatusDown,
+			Error:     ErrShuttingDown.Error(),
+			Duration:  "0s",
+			CheckedAt: r.now(),
+		}}, report.Checks...)
+	}
+	return report
+}
+
+// run 并发执行符合条件的检查项
+func (r *Registry) run(ctx context.Context, include func(*check) bool) Report {
+	r.mu.RLock()
+	var checks []*check
+	for _, c := range r.checks {
+		if include(c) {
+			checks = append(checks, c)
+		}
+	}
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			results[i] = r.execute(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, res := range results {
+		if res.Status == StatusDown && !res.Optional {
+			report.Status = StatusDown
+		}
+	}
+	sort.Slice(report.Checks, func(i, j int) bool { return report.Checks[i].Name < report.Checks[j].Name })
+	return report
+}
+
+// execute 执行单个检查项，缓存期内直接返回上次结果
+// 检查不随调用方的上下文取消：结果会被缓存并返回给其他调用方，
+// 探测客户端断开或超时不应使检查在整个缓存期内显示为失败
+func (r *Registry) execute(ctx context.Context, c *check) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := r.now()
+	if !c.result.CheckedAt.IsZero() && now.Sub(c.result.CheckedAt) < r.cacheTTL {
+		return c.result
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.opts.Timeout)
+	defer cancel()
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- c.checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errChan:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := Result{
+		Name:      c.name,
+		Status:    StatusUp,
+		Optional:  c.opts.Optional,
+		Duration:  r.now().Sub(now).String(),
+		CheckedAt: now,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	c.result = result
+	return result
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	var calls int32
+	reg := NewRegistry(20*time.Millisecond, time.Minute)
+	reg.Register("database", CheckerFunc(func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}), CheckOptions{})
+	reg.Register("cache", CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}), CheckOptions{Optional: true})
+	reg.Register("process", CheckerFunc(func(ctx context.Context) error {
+		return nil
+	}), CheckOptions{Liveness: true})
+
+	// 非关键检查失败不影响整体状态
+	report := reg.Readiness(context.Background())
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Len(t, report.Checks, 3)
+	assert.Equal(t, "cache", report.Checks[0].Name)
+	assert.Equal(t, StatusDown, report.Checks[0].Status)
+
+	// 缓存期内不重复执行检查
+	reg.Readiness(context.Background())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 存活检查只包含标记为 Liveness 的检查项
+	live := reg.Liveness(context.Background())
+	assert.Equal(t, StatusUp, live.Status)
+	assert.Len(t, live.Checks, 1)
+
+	// 关闭期间就绪检查失败，存活检查不受影响
+	reg.MarkShuttingDown()
+	assert.Equal(t, StatusDown, reg.Readiness(context.Background()).Status)
+	assert.Equal(t, StatusUp, reg.Liveness(context.Background()).Status)
+}
+
+func TestRegistryTimeout(t *testing.T) {
+	reg := NewRegistry(10*time.Millisecond, 0)
+	reg.Register("slow", CheckerFunc(func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}), CheckOptions{})
+
+	start := time.Now()
+	report := reg.Readiness(context.Background())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
+
+func TestRegistryCallerCanceled(t *testing.T) {
+	reg := NewRegistry(time.Second, time.Minute)
+	reg.Register("database", CheckerFunc(func(ctx context.Context) error {
+		select {
+		case <-time.After(20 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}), CheckOptions{})
+
+	// 首个调用方在检查完成前断开
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, StatusUp, reg.Readiness(ctx).Status)
+
+	// 缓存的是检查本身的结果，而不是调用方的取消
+	report := reg.Readiness(context.Background())
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Empty(t, report.Checks[0].Error)
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+
+	"go-api-mono/internal/pkg/errors"
+)
+
+// APIKeyOptions API Key 认证中间件选项
+type APIKeyOptions struct {
+	Header string // 携带 API Key 的请求头
+}
+
+// DefaultAPIKeyOptions 默认 API Key 认证选项
+var DefaultAPIKeyOptions = APIKeyOptions{
+	Header: "X-API-Key",
+}
+
+// APIKey 创建 API Key 认证中间件
+// 请求携带的 API Key 属于 keys 时写入上下文的 APIKeyKey，供按 API Key 限流使用；
+// 携带未知的 API Key 时返回 401，未携带时直接放行，是否允许访问由其他认证方式决定
+func APIKey(keys []string, opts APIKeyOptions) Middleware {
+	// 按摘要查找，比较耗时与 API Key 的内容无关
+	valid := make(map[[sha256.Size]byte]bool, len(keys))
+	for _, key := range keys {
+		valid[sha256.Sum256([]byte(key))] = true
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			apiKey := r.Header.Get(opts.Header)
+			if apiKey == "" {
+				next(w, r)
+				return
+			}
+			if !valid[sha256.Sum256([]byte(apiKey))] {
+				writeError(w, r, errors.New(errors.ErrCodeUnauthorized, "invalid API key"))
+				return
+			}
+			next(w, r.WithContext(context.WithValue(r.Context(), APIKeyKey, apiKey)))
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey(t *testing.T) {
+	handler := APIKey([]string{"key-a", "key-b"}, DefaultAPIKeyOptions)(func(w http.ResponseWriter, r *http.Request) {
+		apiKey, _ := r.Context().Value(APIKeyKey).(string)
+		w.Write([]byte(apiKey))
+	})
+
+	tests := []struct {
+		name           string
+		apiKey         string
+		expectedStatus int
+		expectedKey    string
+	}{
+		{name: "有效的API Key", apiKey: "key-b", expectedStatus: http.StatusOK, expectedKey: "key-b"},
+		{name: "未携带API Key", expectedStatus: http.StatusOK},
+		{name: "未知的API Key", apiKey: "key-c", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				var body core.Response
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, int(errors.ErrCodeUnauthorized), body.Code)
+				return
+			}
+			assert.Equal(t, tt.expectedKey, w.Body.String())
+		})
+	}
+}
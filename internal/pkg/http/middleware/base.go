@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 
+	"go-api-mono/internal/pkg/core"
 	"go-api-mono/internal/pkg/logger"
 )
 
@@ -22,9 +23,47 @@ func Chain(middlewares ...Middleware) Middleware {
 	}
 }
 
+// Core 将中间件适配为 core.Middleware，供 core 路由组使用
+// 中间件替换后的请求与响应写入器会同步到 core.Context 中
+func Core(middlewares ...Middleware) core.Middleware {
+	m := Chain(middlewares...)
+	return func(next core.HandlerFunc) core.HandlerFunc {
+		return func(c *core.Context) {
+			m(func(w http.ResponseWriter, r *http.Request) {
+				c.Response.Writer = w
+				c.SetRequest(r)
+				next(c)
+			})(c.Response.Writer, c.Request)
+		}
+	}
+}
+
+// Noop 不做任何处理的中间件，用于按配置关闭的功能
+func Noop(next HandlerFunc) HandlerFunc {
+	return next
+}
+
+// Named 将中间件逐个适配为带名称的 core 中间件，名称取自创建中间件的函数，如 "middleware.JWT"
+// Noop 不会出现在结果中
+func Named(middlewares ...Middleware) []core.NamedMiddleware {
+	noop := core.FuncName(Noop)
+	result := make([]core.NamedMiddleware, 0, len(middlewares))
+	for _, m := range middlewares {
+		if name := core.FuncName(m); name != noop {
+			result = append(result, core.NamedMiddleware{Name: name, Middleware: Core(m)})
+		}
+	}
+	return result
+}
+
 // WrapHandler 将处理函数包装为中间件
 func WrapHandler(handler HandlerFunc, log *logger.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		handler(w, r)
 	}
 }
+
+// writeError 以统一的JSON格式写入错误响应
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	core.NewResponse(w, r).Error(err)
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamed(t *testing.T) {
+	named := Named(RequestID(), Noop, Timeout(time.Second))
+
+	var names []string
+	for _, m := range named {
+		names = append(names, m.Name)
+	}
+	assert.Equal(t, []string{"middleware.RequestID", "middleware.Timeout"}, names)
+}
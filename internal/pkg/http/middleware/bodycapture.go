@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/reporting"
+
+	"go.uber.org/zap"
+)
+
+// BodyCaptureOptions 请求体与响应体记录中间件选项
+type BodyCaptureOptions struct {
+	MaxSize      int      // 每个请求体与响应体最多记录的字节数
+	Redact       []string // 额外需要隐藏的 JSON 字段路径，如 "$.card.number"
+	APIKeyHeader string   // 按 API Key 记录时读取的请求头
+}
+
+// DefaultBodyCaptureOptions 默认请求体记录选项
+var DefaultBodyCaptureOptions = BodyCaptureOptions{
+	MaxSize:      4 << 10,
+	APIKeyHeader: "X-API-Key",
+}
+
+// BodyCaptureTargets 需要记录请求体与响应体的请求，满足任一条件即记录
+type BodyCaptureTargets struct {
+	// Routes 路由模式，格式同 RateLimitRoute.Route
+	Routes  []string
+	Users   []string // JWT 中的用户ID
+	APIKeys []string // API Key 请求头的取值
+}
+
+// bodyCaptureSet 编译后的记录目标
+type bodyCaptureSet struct {
+	routes  map[string]bool
+	users   map[string]bool
+	apiKeys map[string]bool
+}
+
+// BodyCaptureRules 可热更新的记录目标
+type BodyCaptureRules struct {
+	current atomic.Pointer[bodyCaptureSet]
+}
+
+// NewBodyCaptureRules 创建记录目标
+func NewBodyCaptureRules(targets BodyCaptureTargets) *BodyCaptureRules {
+	rules := &BodyCaptureRules{}
+	rules.Update(targets)
+	return rules
+}
+
+// Update 原子地替换记录目标，正在处理的请求不受影响
+func (r *BodyCaptureRules) Update(targets BodyCaptureTargets) {
+	set := &bodyCaptureSet{
+		routes:  make(map[string]bool, len(targets.Routes)),
+		users:   make(map[string]bool, len(targets.Users)),
+		apiKeys: make(map[string]bool, len(targets.APIKeys)),
+	}
+	for _, route := range targets.Routes {
+		set.routes[normalizeRoute(route)] = true
+	}
+	for _, user := range targets.Users {
+		set.users[user] = true
+	}
+	for _, key := range targets.APIKeys {
+		set.apiKeys[key] = true
+	}
+	r.current.Store(set)
+}
+
+// matchRoute 判断请求所在路由是否需要记录
+func (s *bodyCaptureSet) matchRoute(req *http.Request) bool {
+	for _, pattern := range routePatterns(core.CurrentRoute(req.Context())) {
+		if s.routes[pattern] {
+			return true
+		}
+	}
+	return false
+}
+
+// BodyCapture 记录指定路由、用户或 API Key 的请求体与响应体，用于排查客户端问题
+// 记录前按 reporting.SensitiveFields 与 opts.Redact 隐藏敏感字段，敏感请求头同样隐藏；
+// 用户在认证之后才能确定，存在按用户记录的目标时所有请求都会暂存至多 MaxSize 字节的内容
+func BodyCapture(log *logger.Logger, rules *BodyCaptureRules, opts BodyCaptureOptions) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			set := rules.current.Load()
+			matched := set.matchRoute(r) || (len(set.apiKeys) > 0 && set.apiKeys[r.Header.Get(opts.APIKeyHeader)])
+			if !matched && len(set.users) == 0 {
+				next(w, r)
+				return
+			}
+
+			r = r.WithContext(reporting.WithScope(r.Context()))
+			reqBody := &captureBuffer{limit: opts.MaxSize}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = &captureReader{ReadCloser: r.Body, buf: reqBody}
+			}
+			cw := &captureWriter{ResponseWriter: w, status: http.StatusOK, body: captureBuffer{limit: opts.MaxSize}}
+
+			next(cw, r)
+
+			if !matched {
+				user := reporting.UserFromContext(r.Context())
+				if user == nil || !set.users[user.ID] {
+					return
+				}
+			}
+
+			route := ""
+			if rt := core.CurrentRoute(r.Context()); rt != nil {
+				route = rt.Path
+			}
+			log.WithContext(r.Context()).Info("HTTP Body",
+				zap.String("method", r.Method),
+				zap.String("url", reporting.RedactURL(r.URL)),
+				zap.String("route", route),
+				zap.Int("status", cw.status),
+				zap.Any("request_headers", reporting.RedactHeaders(r.Header)),
+				zap.String("request_body", formatBody(r.Header.Get("Content-Type"), reqBody, opts.Redact)),
+				zap.Bool("request_truncated", reqBody.truncated),
+				zap.String("response_body", formatBody(cw.Header().Get("Content-Type"), &cw.body, opts.Redact)),
+				zap.Bool("response_truncated", cw.body.truncated),
+				zap.String("request_id", core.RequestID(r.Context())),
+			)
+		}
+	}
+}
+
+// formatBody 按内容类型隐藏敏感字段，无法识别的二进制内容只记录长度
+func formatBody(contentType string, body *captureBuffer, redact []string) string {
+	if body.Len() == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return string(reporting.RedactJSON(body.Bytes(), redact))
+	case mediaType == "application/x-www-form-urlencoded":
+		return string(reporting.RedactForm(body.Bytes()))
+	case strings.HasPrefix(mediaType, "text/"):
+		return body.String()
+	default:
+		return fmt.Sprintf("[%d bytes of %s]", body.Len(), orDash(mediaType))
+	}
+}
+
+// captureBuffer 至多保存 limit 字节的缓冲区，超出部分丢弃并标记截断
+type captureBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// capture 保存 p 中不超过剩余容量的部分
+func (b *captureBuffer) capture(p []byte) {
+	if remaining := b.limit - b.Len(); len(p) > remaining {
+		p = p[:max(remaining, 0)]
+		b.truncated = true
+	}
+	b.Write(p)
+}
+
+// captureReader 在处理器读取请求体的同时保存读取的内容
+type captureReader struct {
+	io.ReadCloser
+	buf *captureBuffer
+}
+
+// Read 实现 io.Reader 接口
+func (r *captureReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.buf.capture(p[:n])
+	return n, err
+}
+
+// captureWriter 在写出响应的同时保存状态码与响应体
+type captureWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        captureBuffer
+}
+
+// WriteHeader 实现 http.ResponseWriter 接口
+func (w *captureWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write 实现 http.ResponseWriter 接口
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.body.capture(b[:n])
+	return n, err
+}
+
+// Unwrap 返回原始的 ResponseWriter，供 http.ResponseController 使用
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/reporting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyCapture(t *testing.T) {
+	log, logs := newObservedLogger()
+	rules := NewBodyCaptureRules(BodyCaptureTargets{
+		Routes:  []string{"POST /api/orders"},
+		Users:   []string{"7"},
+		APIKeys: []string{"partner-key"},
+	})
+	opts := DefaultBodyCaptureOptions
+	opts.MaxSize = 64
+	opts.Redact = []string{"$.card.number"}
+
+	srv := core.NewServer(core.ServerOptions{Logger: logger.NewNop()})
+	srv.Use(Core(BodyCapture(log, rules, opts)))
+	v1 := srv.API("/api", core.VersioningOptions{Default: "v1"}).Version("v1")
+	v1.Use(Core(func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if user := r.Header.Get("X-User"); user != "" {
+				reporting.SetUser(r.Context(), reporting.User{ID: user})
+			}
+			next(w, r)
+		}
+	}))
+	echo := core.Adapt(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(body)
+	})
+	v1.Handle("POST", "/orders", echo)
+	v1.Handle("POST", "/payments", echo)
+
+	request := func(path, body string, header map[string]string) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		srv.Handler().ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("按路由记录并隐藏敏感字段", func(t *testing.T) {
+		request("/api/v1/orders", `{"password":"p","card":{"number":"4111"}}`, map[string]string{"Authorization": "Bearer secret"})
+
+		require.Equal(t, 1, logs.Len())
+		fields := logs.TakeAll()[0].ContextMap()
+		assert.Equal(t, "/api/v1/orders", fields["route"])
+		assert.Equal(t, int64(http.StatusBadRequest), fields["status"])
+		assert.Equal(t, `{"card":{"number":"[Filtered]"},"password":"[Filtered]"}`, fields["request_body"])
+		assert.Equal(t, fields["request_body"], fields["response_body"])
+		assert.Equal(t, reporting.Filtered, fields["request_headers"].(map[string]string)["Authorization"])
+		assert.Equal(t, false, fields["request_truncated"])
+	})
+
+	t.Run("未命中目标不记录", func(t *testing.T) {
+		request("/api/v1/payments", `{}`, map[string]string{"X-User": "8"})
+		assert.Equal(t, 0, logs.Len())
+	})
+
+	t.Run("按用户记录", func(t *testing.T) {
+		request("/api/v1/payments", `{}`, map[string]string{"X-User": "7"})
+		assert.Equal(t, 1, logs.Len())
+		logs.TakeAll()
+	})
+
+	t.Run("按 API Key 记录并截断", func(t *testing.T) {
+		request("/api/v1/payments", `{"note":"`+strings.Repeat("x", 100)+`"}`, map[string]string{"X-API-Key": "partner-key"})
+
+		require.Equal(t, 1, logs.Len())
+		fields := logs.TakeAll()[0].ContextMap()
+		assert.Len(t, fields["request_body"], 64)
+		assert.Equal(t, true, fields["request_truncated"])
+	})
+
+	t.Run("热更新目标", func(t *testing.T) {
+		rules.Update(BodyCaptureTargets{})
+		request("/api/v1/orders", `{}`, nil)
+		assert.Equal(t, 0, logs.Len())
+	})
+}
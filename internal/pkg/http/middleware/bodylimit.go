@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+
+	"go-api-mono/internal/pkg/errors"
+)
+
+// BodyLimit 创建请求体大小限制中间件
+// 超出限制时返回 413 JSON 错误；limit <= 0 表示不限制
+func BodyLimit(limit int64) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next(w, r)
+				return
+			}
+
+			// Content-Length 已知且超限时直接拒绝
+			if r.ContentLength > limit {
+				writeError(w, r, newBodyTooLargeError(limit))
+				return
+			}
+
+			lw := &bodyLimitWriter{ResponseWriter: w}
+			r.Body = &maxBodyReader{
+				ReadCloser: r.Body,
+				remaining:  limit,
+				onExceed: func() {
+					// 处理器尚未写出响应头时才能返回 413，之后的写入全部丢弃
+					if lw.wroteHeader {
+						return
+					}
+					writeError(lw.ResponseWriter, r, newBodyTooLargeError(limit))
+					lw.wroteHeader = true
+					lw.aborted = true
+				},
+			}
+
+			next(lw, r)
+		}
+	}
+}
+
+// newBodyTooLargeError 创建请求体过大错误
+func newBodyTooLargeError(limit int64) error {
+	return errors.New(errors.ErrCodeRequestEntityTooLarge, "request body too large").
+		WithDetails(map[string]interface{}{"limit": limit})
+}
+
+// errBodyTooLarge 读取超限时返回给处理器的错误
+var errBodyTooLarge = errors.New(errors.ErrCodeRequestEntityTooLarge, "request body too large")
+
+// maxBodyReader 限制可读取的字节数
+type maxBodyReader struct {
+	io.ReadCloser
+	remaining int64
+	exceeded  bool
+	onExceed  func()
+}
+
+// Read 实现 io.Reader 接口
+func (r *maxBodyReader) Read(p []byte) (int, error) {
+	if r.exceeded {
+		return 0, errBodyTooLarge
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	// 多读一个字节用于判断是否超限
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.ReadCloser.Read(p)
+	if int64(n) <= r.remaining {
+		r.remaining -= int64(n)
+		return n, err
+	}
+
+	n = int(r.remaining)
+	r.remaining = 0
+	r.exceeded = true
+	r.onExceed()
+	return n, errBodyTooLarge
+}
+
+// bodyLimitWriter 记录响应头是否已写出，并在返回 413 后丢弃处理器的写入
+type bodyLimitWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	aborted     bool
+}
+
+// WriteHeader 实现 http.ResponseWriter 接口
+func (w *bodyLimitWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write 实现 http.ResponseWriter 接口
+func (w *bodyLimitWriter) Write(b []byte) (int, error) {
+	if w.aborted {
+		return 0, errBodyTooLarge
+	}
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap 返回原始的 ResponseWriter，供 http.ResponseController 使用
+func (w *bodyLimitWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		contentLength  int64
+		expectedStatus int
+	}{
+		{
+			name:           "未超出限制",
+			body:           `{"name":"ok"}`,
+			contentLength:  -1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Content-Length 超出限制",
+			body:           strings.Repeat("a", 32),
+			contentLength:  32,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "分块传输读取时超出限制",
+			body:           strings.Repeat("a", 32),
+			contentLength:  -1,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := BodyLimit(16)(func(w http.ResponseWriter, r *http.Request) {
+				if _, err := io.ReadAll(r.Body); err != nil {
+					// 处理器自身的错误响应应被丢弃
+					http.Error(w, "bad request", http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.ContentLength = tt.contentLength
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusRequestEntityTooLarge {
+				var resp map[string]interface{}
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, "request body too large", resp["message"])
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-api-mono/internal/pkg/concurrency"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/errors"
+)
+
+// ConcurrencyOptions 并发限制中间件选项
+type ConcurrencyOptions struct {
+	// Routes 路由模式与优先级的绑定，路由模式与限流策略的绑定规则相同，
+	// 如 "POST /api/auth/login" 或 "/healthz"
+	Routes          map[string]concurrency.Priority
+	DefaultPriority concurrency.Priority // 未绑定的路由使用的优先级
+	RetryAfter      time.Duration        // 被拒绝的请求建议的重试间隔
+}
+
+// DefaultConcurrencyOptions 默认并发限制选项
+var DefaultConcurrencyOptions = ConcurrencyOptions{
+	DefaultPriority: concurrency.PriorityNormal,
+	RetryAfter:      time.Second,
+}
+
+// ConcurrencyLimit 创建自适应并发限制中间件
+// 并发超出当前上限时立即返回 503 与 Retry-After，而不是排队等待直到写超时；
+// 返回 503 或 504 的请求使并发上限收缩，处理超时由 Timeout 中间件写出 503
+func ConcurrencyLimit(limiter *concurrency.Limiter, opts ConcurrencyOptions) Middleware {
+	routes := make(map[string]concurrency.Priority, len(opts.Routes))
+	for pattern, priority := range opts.Routes {
+		routes[normalizeRoute(pattern)] = priority
+	}
+	retryAfter := max(ceilSeconds(opts.RetryAfter), 1)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			priority := opts.DefaultPriority
+			for _, pattern := range routePatterns(core.CurrentRoute(r.Context())) {
+				if p, ok := routes[pattern]; ok {
+					priority = p
+					break
+				}
+			}
+
+			token, ok := limiter.Acquire(priority)
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				writeError(w, r, errors.New(errors.ErrCodeServiceUnavailable, "Server is overloaded").
+					WithDetails(map[string]interface{}{"retry_after": retryAfter, "priority": priority.String()}))
+				return
+			}
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				dropped := sw.status == http.StatusServiceUnavailable ||
+					sw.status == http.StatusGatewayTimeout
+				token.Release(dropped)
+			}()
+
+			next(sw, r)
+		}
+	}
+}
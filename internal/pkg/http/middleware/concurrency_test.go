@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-mono/internal/pkg/concurrency"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	limiter := concurrency.NewLimiter(concurrency.Options{InitialLimit: 2, MinLimit: 1, MaxLimit: 4, LowPriorityShare: 0.5})
+	m := metrics.New()
+	require.NoError(t, m.RegisterConcurrencyLimiter(limiter))
+
+	opts := DefaultConcurrencyOptions
+	opts.RetryAfter = 2 * time.Second
+	opts.Routes = map[string]concurrency.Priority{
+		"post /api/auth/login": concurrency.PriorityCritical,
+		"GET /api/users":       concurrency.PriorityLow,
+	}
+
+	// 处理器阻塞直到 release 关闭，模拟变慢的下游
+	release := make(chan struct{})
+	block := func(c *core.Context) {
+		<-release
+		c.Response.Success(nil)
+	}
+
+	srv := core.NewServer(core.ServerOptions{Logger: logger.NewNop()})
+	srv.Use(Core(ConcurrencyLimit(limiter, opts)))
+	v1 := srv.API("/api", core.VersioningOptions{Default: "v1"}).Version("v1")
+	v1.Handle("GET", "/users", block)
+	v1.Handle("GET", "/users/{id}", block)
+	v1.Handle("POST", "/auth/login", block)
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	// 占满普通优先级的并发上限
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, request(http.MethodGet, "/api/v1/users/1").Code)
+		}()
+	}
+	require.Eventually(t, func() bool { return limiter.InFlight() == 2 }, time.Second, time.Millisecond)
+
+	t.Run("超出上限的请求立即返回503", func(t *testing.T) {
+		w := request(http.MethodGet, "/api/v1/users/2")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+
+		var body struct {
+			Details map[string]interface{} `json:"details"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "normal", body.Details["priority"])
+	})
+
+	t.Run("低优先级路由先被拒绝", func(t *testing.T) {
+		assert.Equal(t, http.StatusServiceUnavailable, request(http.MethodGet, "/api/users").Code)
+	})
+
+	t.Run("关键路由仍然可用", func(t *testing.T) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, http.StatusOK, request(http.MethodPost, "/api/v1/auth/login").Code)
+		}()
+		require.Eventually(t, func() bool { return limiter.InFlight() == 3 }, time.Second, time.Millisecond)
+	})
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 0, limiter.InFlight())
+
+	expected := `
+# HELP concurrency_rejected_total Total number of requests shed by the concurrency limiter.
+# TYPE concurrency_rejected_total counter
+concurrency_rejected_total{priority="critical"} 0
+concurrency_rejected_total{priority="low"} 1
+concurrency_rejected_total{priority="normal"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry(), strings.NewReader(expected), "concurrency_rejected_total"))
+}
+
+func TestConcurrencyLimitBackoff(t *testing.T) {
+	limiter := concurrency.NewLimiter(concurrency.Options{InitialLimit: 10, MinLimit: 1, MaxLimit: 10, BackoffRatio: 0.5})
+	handler := ConcurrencyLimit(limiter, DefaultConcurrencyOptions)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	// 下游不可用的响应使并发上限收缩
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, 5, limiter.Limit())
+
+	// 路由超时在内层请求上生效，由 Timeout 写出的 503 使并发上限收缩
+	timeout := Chain(ConcurrencyLimit(limiter, DefaultConcurrencyOptions), Timeout(10*time.Millisecond))(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	w := httptest.NewRecorder()
+	timeout(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, 2, limiter.Limit())
+}
@@ -1,24 +1,290 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go-api-mono/internal/pkg/errors"
 )
 
+// CORSOptions 跨域策略选项
+//
+// AllowOrigins 中的每一项可以是：
+//   - "*"：允许任意来源（不能与 AllowCredentials 同时使用）
+//   - 精确来源，如 "https://app.example.com"
+//   - 通配子域名，如 "https://*.example.com"，不匹配 example.com 本身
+//   - 以 "^" 开头的正则表达式，如 "^https://(app|admin)\.example\.com$"
+type CORSOptions struct {
+	AllowOrigins     []string      // 允许的来源
+	AllowMethods     []string      // 允许的请求方法
+	AllowHeaders     []string      // 允许的请求头，"*" 表示允许预检请求声明的任意请求头
+	ExposeHeaders    []string      // 允许浏览器读取的响应头
+	AllowCredentials bool          // 是否允许携带 Cookie 等凭证
+	MaxAge           time.Duration // 预检结果的缓存时间
+}
+
+// DefaultCORSOptions 默认跨域策略选项
+var DefaultCORSOptions = CORSOptions{
+	AllowOrigins: []string{"*"},
+	AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+	AllowHeaders: []string{
+		"Content-Type", "Authorization", "X-Request-ID", "Idempotency-Key", "API-Version", "traceparent",
+		"X-CSRF-Token", "X-Client-Type",
+	},
+	ExposeHeaders: []string{
+		"X-Request-ID", "X-Trace-ID", "API-Version", "Deprecation", "Sunset", "Link", "Idempotent-Replayed",
+		"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After",
+	},
+	MaxAge: 12 * time.Hour,
+}
+
+// CORSPolicy 可热更新的跨域策略
+type CORSPolicy struct {
+	current atomic.Pointer[compiledCORS]
+}
+
+// compiledCORS 编译后的跨域策略
+type compiledCORS struct {
+	opts CORSOptions
+
+	anyOrigin bool
+	origins   map[string]bool
+	wildcards []wildcardOrigin
+	patterns  []*regexp.Regexp
+
+	anyHeader bool
+	methods   map[string]bool
+	headers   map[string]bool
+
+	allowMethods  string
+	allowHeaders  string
+	exposeHeaders string
+	maxAge        string
+}
+
+// wildcardOrigin 通配子域名来源，如 https://*.example.com
+type wildcardOrigin struct {
+	prefix string // 协议部分，如 "https://"
+	suffix string // 域名后缀，如 ".example.com"
+}
+
+// match 判断来源是否为后缀域名的子域名
+func (w wildcardOrigin) match(origin string) bool {
+	if len(origin) <= len(w.prefix)+len(w.suffix) {
+		return false
+	}
+	if !strings.HasPrefix(origin, w.prefix) || !strings.HasSuffix(origin, w.suffix) {
+		return false
+	}
+	sub := origin[len(w.prefix) : len(origin)-len(w.suffix)]
+	return !strings.ContainsAny(sub, "/:@")
+}
+
+// NewCORSPolicy 根据选项编译跨域策略
+func NewCORSPolicy(opts CORSOptions) (*CORSPolicy, error) {
+	c, err := compileCORS(opts)
+	if err != nil {
+		return nil, err
+	}
+	p := &CORSPolicy{}
+	p.current.Store(c)
+	return p, nil
+}
+
+// Update 原子地替换跨域策略，选项无效时保留原有策略
+func (p *CORSPolicy) Update(opts CORSOptions) error {
+	c, err := compileCORS(opts)
+	if err != nil {
+		return err
+	}
+	p.current.Store(c)
+	return nil
+}
+
+// compileCORS 编译跨域策略选项
+func compileCORS(opts CORSOptions) (*compiledCORS, error) {
+	p := &compiledCORS{
+		opts:    opts,
+		origins: make(map[string]bool),
+		methods: make(map[string]bool),
+		headers: make(map[string]bool),
+	}
+
+	for _, origin := range opts.AllowOrigins {
+		switch {
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.HasPrefix(origin, "^"):
+			re, err := regexp.Compile(origin)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cors origin pattern %q: %w", origin, err)
+			}
+			p.patterns = append(p.patterns, re)
+		case strings.Contains(origin, "://*."):
+			i := strings.Index(origin, "*")
+			p.wildcards = append(p.wildcards, wildcardOrigin{
+				prefix: strings.ToLower(origin[:i]),
+				suffix: strings.ToLower(origin[i+1:]),
+			})
+		case strings.Contains(origin, "*"):
+			return nil, fmt.Errorf("invalid cors origin %q: wildcard is only allowed as a subdomain", origin)
+		default:
+			p.origins[strings.ToLower(origin)] = true
+		}
+	}
+	if p.anyOrigin && opts.AllowCredentials {
+		return nil, fmt.Errorf("cors origin \"*\" cannot be used with credentials")
+	}
+
+	for _, method := range opts.AllowMethods {
+		p.methods[strings.ToUpper(method)] = true
+	}
+	for _, header := range opts.AllowHeaders {
+		if header == "*" {
+			p.anyHeader = true
+			continue
+		}
+		p.headers[strings.ToLower(header)] = true
+	}
+
+	p.allowMethods = strings.Join(opts.AllowMethods, ", ")
+	p.allowHeaders = strings.Join(opts.AllowHeaders, ", ")
+	p.exposeHeaders = strings.Join(opts.ExposeHeaders, ", ")
+	if opts.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(int(opts.MaxAge.Seconds()))
+	}
+	return p, nil
+}
+
+// AllowOrigin 判断来源是否被允许
+func (p *CORSPolicy) AllowOrigin(origin string) bool {
+	return p.current.Load().allowOrigin(origin)
+}
+
+// allowOrigin 判断来源是否被允许
+func (p *compiledCORS) allowOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	lower := strings.ToLower(origin)
+	if p.origins[lower] {
+		return true
+	}
+	for _, w := range p.wildcards {
+		if w.match(lower) {
+			return true
+		}
+	}
+	for _, re := range p.patterns {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowRequestHeaders 检查预检请求声明的请求头，返回第一个不被允许的请求头
+func (p *compiledCORS) allowRequestHeaders(requested string) (string, bool) {
+	if p.anyHeader {
+		return "", true
+	}
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" && !p.headers[strings.ToLower(header)] {
+			return header, false
+		}
+	}
+	return "", true
+}
+
 // CORS 跨域中间件
-func CORS() Middleware {
+// 预检请求由中间件直接应答，不会进入后续的中间件与处理器；
+// 不被允许的预检请求返回 403，不被允许来源的普通请求照常处理但不携带跨域响应头
+// 每个请求使用当时生效的策略，调用 Update 后立即生效
+func CORS(p *CORSPolicy) Middleware {
 	return func(next HandlerFunc) HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
-			w.Header().Set("Access-Control-Max-Age", "86400")
+			policy := p.current.Load()
+			header := w.Header()
+			origin := r.Header.Get("Origin")
+
+			// 响应内容取决于来源时，告知缓存按 Origin 区分
+			if !policy.anyOrigin {
+				header.Add("Vary", "Origin")
+			}
 
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if origin == "" {
+				next(w, r)
 				return
 			}
 
-			next(w, r)
+			if !policy.allowOrigin(origin) {
+				if preflight {
+					rejectPreflight(w, r, "origin not allowed", origin)
+					return
+				}
+				next(w, r)
+				return
+			}
+
+			if policy.anyOrigin {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+			if policy.opts.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if policy.exposeHeaders != "" {
+					header.Set("Access-Control-Expose-Headers", policy.exposeHeaders)
+				}
+				next(w, r)
+				return
+			}
+
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+
+			method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+			if !policy.methods[method] {
+				rejectPreflight(w, r, "method not allowed", method)
+				return
+			}
+			requested := r.Header.Get("Access-Control-Request-Headers")
+			if h, ok := policy.allowRequestHeaders(requested); !ok {
+				rejectPreflight(w, r, "header not allowed", h)
+				return
+			}
+
+			header.Set("Access-Control-Allow-Methods", policy.allowMethods)
+			if policy.anyHeader {
+				if requested != "" {
+					header.Set("Access-Control-Allow-Headers", requested)
+				}
+			} else if policy.allowHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", policy.allowHeaders)
+			}
+			if policy.maxAge != "" {
+				header.Set("Access-Control-Max-Age", policy.maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
 		}
 	}
 }
+
+// rejectPreflight 拒绝预检请求，不携带任何允许跨域的响应头
+func rejectPreflight(w http.ResponseWriter, r *http.Request, reason, value string) {
+	header := w.Header()
+	header.Del("Access-Control-Allow-Origin")
+	header.Del("Access-Control-Allow-Credentials")
+	writeError(w, r, errors.New(errors.ErrCodeForbidden, "CORS preflight rejected").
+		WithDetails(map[string]string{"reason": reason, "value": value}))
+}
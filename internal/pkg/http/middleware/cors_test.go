@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORSPolicyAllowOrigin(t *testing.T) {
+	policy, err := NewCORSPolicy(CORSOptions{
+		AllowOrigins: []string{
+			"https://app.example.com",
+			"https://*.example.org",
+			`^http://localhost:\d+$`,
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://app.example.com", true},
+		{"https://APP.example.com", true},
+		{"http://app.example.com", false},
+		{"https://evil.example.com", false},
+		{"https://a.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"https://evil.com/.example.org", false},
+		{"https://evilexample.org", false},
+		{"http://localhost:3000", true},
+		{"http://localhost:3000.evil.com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.origin, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.AllowOrigin(tt.origin))
+		})
+	}
+}
+
+func TestNewCORSPolicyInvalid(t *testing.T) {
+	_, err := NewCORSPolicy(CORSOptions{AllowOrigins: []string{"*"}, AllowCredentials: true})
+	assert.Error(t, err)
+
+	_, err = NewCORSPolicy(CORSOptions{AllowOrigins: []string{"https://app.*.com"}})
+	assert.Error(t, err)
+
+	_, err = NewCORSPolicy(CORSOptions{AllowOrigins: []string{"^(unclosed"}})
+	assert.Error(t, err)
+}
+
+func TestCORS(t *testing.T) {
+	policy, err := NewCORSPolicy(CORSOptions{
+		AllowOrigins:     []string{"https://app.example.com"},
+		AllowMethods:     []string{"GET", "POST"},
+		AllowHeaders:     []string{"Content-Type", "Authorization"},
+		ExposeHeaders:    []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	require.NoError(t, err)
+
+	var called bool
+	handler := CORS(policy)(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		headers    map[string]string
+		wantStatus int
+		wantCalled bool
+		wantHeader map[string]string
+	}{
+		{
+			name:       "非跨域请求",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:       "允许的来源",
+			method:     http.MethodGet,
+			headers:    map[string]string{"Origin": "https://app.example.com"},
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-ID",
+			},
+		},
+		{
+			name:       "不允许的来源不携带跨域响应头",
+			method:     http.MethodGet,
+			headers:    map[string]string{"Origin": "https://evil.com"},
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "预检请求",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "content-type, authorization",
+			},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.com",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "Content-Type, Authorization",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:   "预检请求来源不被允许",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://evil.com",
+				"Access-Control-Request-Method": "POST",
+			},
+			wantStatus: http.StatusForbidden,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "预检请求方法不被允许",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": "DELETE",
+			},
+			wantStatus: http.StatusForbidden,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "预检请求头不被允许",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "GET",
+				"Access-Control-Request-Headers": "X-Custom",
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "非预检的OPTIONS请求交给后续处理器",
+			method:     http.MethodOptions,
+			headers:    map[string]string{"Origin": "https://app.example.com"},
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest(tt.method, "/users", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantCalled, called)
+			assert.Contains(t, w.Header().Values("Vary"), "Origin")
+			for k, v := range tt.wantHeader {
+				assert.Equal(t, v, w.Header().Get(k), k)
+			}
+		})
+	}
+}
+
+func TestCORSPolicyUpdate(t *testing.T) {
+	policy, err := NewCORSPolicy(CORSOptions{AllowOrigins: []string{"https://app.example.com"}})
+	require.NoError(t, err)
+	handler := CORS(policy)(func(w http.ResponseWriter, r *http.Request) {})
+
+	allowed := func(origin string) string {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Header().Get("Access-Control-Allow-Origin")
+	}
+	assert.Equal(t, "https://app.example.com", allowed("https://app.example.com"))
+	assert.Empty(t, allowed("https://admin.example.com"))
+
+	// 更新后已创建的中间件立即使用新策略
+	require.NoError(t, policy.Update(CORSOptions{AllowOrigins: []string{"https://admin.example.com"}}))
+	assert.Empty(t, allowed("https://app.example.com"))
+	assert.Equal(t, "https://admin.example.com", allowed("https://admin.example.com"))
+
+	// 无效的选项保留原有策略
+	require.Error(t, policy.Update(CORSOptions{AllowOrigins: []string{"*"}, AllowCredentials: true}))
+	assert.Equal(t, "https://admin.example.com", allowed("https://admin.example.com"))
+}
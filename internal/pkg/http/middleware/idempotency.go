@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-api-mono/internal/pkg/auth"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/errors"
+	"go-api-mono/internal/pkg/idempotency"
+)
+
+// IdempotencyOptions 幂等中间件选项
+type IdempotencyOptions struct {
+	HeaderName   string        // 幂等键请求头
+	TTL          time.Duration // 记录保留时间
+	Methods      []string      // 需要处理的请求方法
+	MaxKeyLength int           // 幂等键最大长度
+}
+
+// DefaultIdempotencyOptions 默认幂等选项
+var DefaultIdempotencyOptions = IdempotencyOptions{
+	HeaderName:   "Idempotency-Key",
+	TTL:          24 * time.Hour,
+	Methods:      []string{http.MethodPost, http.MethodPatch},
+	MaxKeyLength: 255,
+}
+
+// Idempotency 创建幂等中间件
+// 首个请求的响应会被保存，相同幂等键的重复请求直接重放该响应
+func Idempotency(store idempotency.Store, opts IdempotencyOptions) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(opts.HeaderName)
+			if key == "" || !containsMethod(opts.Methods, r.Method) {
+				next(w, r)
+				return
+			}
+			if len(key) > opts.MaxKeyLength {
+				writeError(w, r, errors.New(errors.ErrCodeBadRequest, "invalid idempotency key").
+					WithDetails(map[string]interface{}{"max_length": opts.MaxKeyLength}))
+				return
+			}
+
+			// 读取请求体以计算指纹，之后恢复给处理器
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, r, errors.Wrap(err, errors.ErrCodeBadRequest, "failed to read request body"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scopedKey := idempotencyScope(r, key)
+			fingerprint := requestFingerprint(r, body)
+
+			record, acquired, err := store.Acquire(r.Context(), scopedKey, fingerprint, opts.TTL)
+			if err != nil {
+				writeError(w, r, errors.Wrap(err, errors.ErrCodeInternal, "failed to check idempotency key"))
+				return
+			}
+
+			if !acquired {
+				switch {
+				case record.Fingerprint != fingerprint:
+					writeError(w, r, errors.New(errors.ErrCodeConflict,
+						"idempotency key already used with a different request payload"))
+				case !record.Completed:
+					w.Header().Set("Retry-After", "1")
+					writeError(w, r, errors.New(errors.ErrCodeConflict,
+						"a request with this idempotency key is still in progress"))
+				default:
+					replayResponse(w, record)
+				}
+				return
+			}
+
+			// 处理器超时会取消请求上下文，但记录仍需保存
+			storeCtx := context.WithoutCancel(r.Context())
+			rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			defer func() {
+				// 处理器 panic 或服务端错误时释放幂等键，允许客户端重试
+				if !completed {
+					_ = store.Release(storeCtx, scopedKey)
+				}
+			}()
+
+			next(rec, r)
+
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+			completed = true
+			if err := store.Complete(storeCtx, &idempotency.Record{
+				Key:         scopedKey,
+				Fingerprint: fingerprint,
+				StatusCode:  rec.status,
+				Header:      replayableHeader(w.Header()),
+				Body:        rec.body.Bytes(),
+			}); err != nil {
+				_ = store.Release(storeCtx, scopedKey)
+			}
+		}
+	}
+}
+
+// idempotencyScope 将幂等键限定在用户、方法与路径范围内，避免不同调用方之间冲突
+// 未认证的请求按客户端IP区分，避免不同客户端使用相同的键时互相重放响应
+func idempotencyScope(r *http.Request, key string) string {
+	subject := "anonymous:" + core.ClientIP(r)
+	if claims, ok := r.Context().Value(ClaimsKey).(*auth.Claims); ok && claims != nil {
+		subject = strconv.FormatUint(uint64(claims.UserID), 10)
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s %s|%s", subject, r.Method, r.URL.Path, key)))
+	return hex.EncodeToString(sum[:])
+}
+
+// requestFingerprint 计算请求指纹
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.RequestURI()))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// replayResponse 重放已保存的响应
+func replayResponse(w http.ResponseWriter, record *idempotency.Record) {
+	for k, vv := range record.Header {
+		w.Header()[k] = vv
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// replayableHeader 复制可重放的响应头，排除与单次请求相关的头部
+func replayableHeader(h http.Header) http.Header {
+	header := h.Clone()
+	header.Del("X-Request-ID")
+	header.Del("Set-Cookie")
+	header.Del("Date")
+	return header
+}
+
+// containsMethod 判断方法是否在列表中
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordingWriter 在写出响应的同时捕获状态码和响应体
+type recordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// WriteHeader 实现 http.ResponseWriter 接口
+func (w *recordingWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write 实现 http.ResponseWriter 接口
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap 返回原始的 ResponseWriter，供 http.ResponseController 使用
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
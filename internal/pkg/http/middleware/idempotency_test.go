@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-mono/internal/pkg/idempotency"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	var calls int32
+	store := idempotency.NewMemoryStore()
+	handler := Idempotency(store, DefaultIdempotencyOptions)(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"call":%d}`, n)
+	})
+
+	sendFrom := func(remoteAddr, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", strings.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Idempotency-Key", key)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+	send := func(key, body string) *httptest.ResponseRecorder {
+		return sendFrom("192.0.2.1:1234", key, body)
+	}
+
+	first := send("key-1", `{"email":"a@example.com"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, `{"call":1}`, first.Body.String())
+
+	// 相同键与相同请求体：重放首次响应
+	replay := send("key-1", `{"email":"a@example.com"}`)
+	assert.Equal(t, http.StatusCreated, replay.Code)
+	assert.Equal(t, `{"call":1}`, replay.Body.String())
+	assert.Equal(t, "true", replay.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// 相同键但请求体不同：409
+	mismatch := send("key-1", `{"email":"b@example.com"}`)
+	assert.Equal(t, http.StatusConflict, mismatch.Code)
+
+	// 其他未认证的客户端使用相同的键：互不影响
+	other := sendFrom("198.51.100.7:4321", "key-1", `{"email":"b@example.com"}`)
+	assert.Equal(t, http.StatusCreated, other.Code)
+	assert.Equal(t, `{"call":2}`, other.Body.String())
+	assert.Empty(t, other.Header().Get("Idempotent-Replayed"))
+
+	// 首个请求仍在处理中：409 并提示重试
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", strings.NewReader(`{}`))
+	req.Header.Set("Idempotency-Key", "pending")
+	inflightKey := idempotencyScope(req, "pending")
+	_, acquired, err := store.Acquire(context.Background(), inflightKey, requestFingerprint(req, []byte(`{}`)), time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
+
+func TestIdempotencyReleasesOnServerError(t *testing.T) {
+	var calls int32
+	handler := Idempotency(idempotency.NewMemoryStore(), DefaultIdempotencyOptions)(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, expected := range []int{http.StatusInternalServerError, http.StatusOK} {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		req.Header.Set("Idempotency-Key", "retry")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		assert.Equal(t, expected, w.Code)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -2,11 +2,15 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"go-api-mono/internal/pkg/auth"
 	"go-api-mono/internal/pkg/errors"
+	"go-api-mono/internal/pkg/reporting"
 )
 
 // JWTOptions JWT中间件选项
@@ -15,6 +19,11 @@ type JWTOptions struct {
 	SkipPaths      []string // 跳过验证的路径
 	ClaimsKey      string   // Claims在上下文中的键
 	ContextUserKey string   // 用户信息在上下文中的键
+
+	// Cookie 会话，CookieName 为空时只接受 Authorization 请求头
+	CookieName     string   // 会话 Cookie 名称
+	CSRFHeaderName string   // 携带 CSRF 令牌的请求头名称
+	TrustedOrigins []string // 除同源外允许发起 Cookie 认证写请求的来源
 }
 
 // DefaultJWTOptions 默认JWT选项
@@ -23,6 +32,7 @@ var DefaultJWTOptions = JWTOptions{
 	SkipPaths:      []string{"/api/v1/auth/login", "/api/v1/auth/register"},
 	ClaimsKey:      string(ClaimsKey),
 	ContextUserKey: string(UserKey),
+	CSRFHeaderName: "X-CSRF-Token",
 }
 
 // JWT 创建JWT认证中间件
@@ -37,40 +47,116 @@ func JWT(jwt *auth.JWT, opts JWTOptions) Middleware {
 				}
 			}
 
-			// 从请求头获取令牌
+			// 优先从请求头获取令牌，未携带时尝试会话 Cookie
+			var token string
+			method := auth.ModeBearer
 			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				http.Error(w, errors.ErrUnauthorized.Error(), http.StatusUnauthorized)
-				return
-			}
-
-			// 验证令牌前缀
-			if !strings.HasPrefix(authHeader, opts.TokenPrefix+" ") {
-				http.Error(w, errors.ErrInvalidToken.Error(), http.StatusUnauthorized)
+			switch {
+			case authHeader != "":
+				// 验证令牌前缀
+				if !strings.HasPrefix(authHeader, opts.TokenPrefix+" ") {
+					writeError(w, r, errors.ErrInvalidToken)
+					return
+				}
+				token = strings.TrimPrefix(authHeader, opts.TokenPrefix+" ")
+			case opts.CookieName != "":
+				cookie, err := r.Cookie(opts.CookieName)
+				if err != nil || cookie.Value == "" {
+					writeError(w, r, errors.ErrUnauthorized)
+					return
+				}
+				token = cookie.Value
+				method = auth.ModeCookie
+			default:
+				writeError(w, r, errors.ErrUnauthorized)
 				return
 			}
 
-			// 提取令牌
-			token := strings.TrimPrefix(authHeader, opts.TokenPrefix+" ")
-
 			// 验证令牌并获取用户信息
 			claims, err := jwt.GetUserFromToken(token)
 			if err != nil {
 				switch {
 				case errors.Is(err, errors.ErrTokenExpired):
-					http.Error(w, errors.ErrTokenExpired.Error(), http.StatusUnauthorized)
+					writeError(w, r, errors.ErrTokenExpired)
 				case errors.Is(err, errors.ErrInvalidToken):
-					http.Error(w, errors.ErrInvalidToken.Error(), http.StatusUnauthorized)
+					writeError(w, r, errors.ErrInvalidToken)
 				default:
-					http.Error(w, errors.ErrUnauthorized.Error(), http.StatusUnauthorized)
+					writeError(w, r, errors.ErrUnauthorized)
 				}
 				return
 			}
 
+			// 浏览器会自动携带 Cookie，Cookie 认证的写请求需要通过 CSRF 校验
+			if method == auth.ModeCookie && !isSafeMethod(r.Method) {
+				if err := verifyCSRF(r, claims, opts); err != nil {
+					writeError(w, r, err)
+					return
+				}
+			}
+
+			// 记录当前用户，供外层的恢复中间件上报错误时使用
+			reporting.SetUser(r.Context(), reporting.User{
+				ID:       strconv.FormatUint(uint64(claims.UserID), 10),
+				Username: claims.Username,
+				Role:     claims.Role,
+			})
+
 			// 将认证信息注入上下文
 			ctx := context.WithValue(r.Context(), ContextKey(opts.ClaimsKey), claims)
 			ctx = context.WithValue(ctx, ContextKey(opts.ContextUserKey), claims)
+			ctx = context.WithValue(ctx, AuthMethodKey, method)
 			next(w, r.WithContext(ctx))
 		}
 	}
 }
+
+// isSafeMethod 判断请求方法是否不会修改服务端状态
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// verifyCSRF 校验 Cookie 认证请求的来源与 CSRF 令牌
+// 请求携带 Origin（或 Referer）时必须同源或属于受信任来源；
+// 请求头中的 CSRF 令牌必须与签名令牌中绑定的值一致，攻击者无法通过写入 Cookie 伪造
+func verifyCSRF(r *http.Request, claims *auth.Claims, opts JWTOptions) error {
+	if origin := requestOrigin(r); origin != "" && !trustedOrigin(r, origin, opts.TrustedOrigins) {
+		return errors.New(errors.ErrCodeForbidden, "cross-site request rejected").
+			WithDetails(map[string]string{"origin": origin})
+	}
+
+	token := r.Header.Get(opts.CSRFHeaderName)
+	if token == "" || claims.CSRF == "" || subtle.ConstantTimeCompare([]byte(token), []byte(claims.CSRF)) != 1 {
+		return errors.New(errors.ErrCodeForbidden, "missing or invalid CSRF token")
+	}
+	return nil
+}
+
+// requestOrigin 返回请求的来源，没有 Origin 请求头时从 Referer 中提取
+func requestOrigin(r *http.Request) string {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		return origin
+	}
+	if referer := r.Header.Get("Referer"); referer != "" {
+		if u, err := url.Parse(referer); err == nil && u.Host != "" {
+			return u.Scheme + "://" + u.Host
+		}
+	}
+	return ""
+}
+
+// trustedOrigin 判断来源是否与请求同源或在受信任列表中
+func trustedOrigin(r *http.Request, origin string, trusted []string) bool {
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, t := range trusted {
+		if strings.EqualFold(strings.TrimSuffix(t, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
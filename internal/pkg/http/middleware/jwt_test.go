@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-mono/internal/pkg/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTCookieSession(t *testing.T) {
+	jwt := auth.New(auth.Config{SigningKey: "test-key", ExpirationTime: time.Hour})
+	token, csrf, err := jwt.GenerateSessionToken(1, "testuser", "user")
+	require.NoError(t, err)
+	bearer, err := jwt.GenerateToken(1, "testuser", "user")
+	require.NoError(t, err)
+
+	opts := DefaultJWTOptions
+	opts.CookieName = "session"
+	opts.TrustedOrigins = []string{"https://app.example.com"}
+
+	var method string
+	handler := JWT(jwt, opts)(func(w http.ResponseWriter, r *http.Request) {
+		method, _ = r.Context().Value(AuthMethodKey).(string)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		headers    map[string]string
+		cookie     string
+		wantStatus int
+		wantMethod string
+	}{
+		{
+			name:       "Bearer令牌无需CSRF令牌",
+			method:     http.MethodPost,
+			headers:    map[string]string{"Authorization": "Bearer " + bearer, "Origin": "https://evil.com"},
+			wantStatus: http.StatusOK,
+			wantMethod: auth.ModeBearer,
+		},
+		{
+			name:       "Cookie认证的安全方法无需CSRF令牌",
+			method:     http.MethodGet,
+			cookie:     token,
+			wantStatus: http.StatusOK,
+			wantMethod: auth.ModeCookie,
+		},
+		{
+			name:       "Cookie认证的写请求缺少CSRF令牌",
+			method:     http.MethodPut,
+			cookie:     token,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "CSRF令牌不匹配",
+			method:     http.MethodPut,
+			cookie:     token,
+			headers:    map[string]string{"X-CSRF-Token": "forged"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "同源请求携带正确的CSRF令牌",
+			method:     http.MethodPut,
+			cookie:     token,
+			headers:    map[string]string{"X-CSRF-Token": csrf, "Origin": "http://example.com"},
+			wantStatus: http.StatusOK,
+			wantMethod: auth.ModeCookie,
+		},
+		{
+			name:       "受信任来源携带正确的CSRF令牌",
+			method:     http.MethodDelete,
+			cookie:     token,
+			headers:    map[string]string{"X-CSRF-Token": csrf, "Origin": "https://app.example.com"},
+			wantStatus: http.StatusOK,
+			wantMethod: auth.ModeCookie,
+		},
+		{
+			name:       "跨站来源即使携带CSRF令牌也被拒绝",
+			method:     http.MethodPut,
+			cookie:     token,
+			headers:    map[string]string{"X-CSRF-Token": csrf, "Referer": "https://evil.com/page"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Bearer令牌不能当作Cookie会话使用",
+			method:     http.MethodPut,
+			cookie:     bearer,
+			headers:    map[string]string{"X-CSRF-Token": ""},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "未携带任何凭证",
+			method:     http.MethodGet,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Authorization 前缀错误",
+			method:     http.MethodGet,
+			headers:    map[string]string{"Authorization": "Token " + bearer},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "无效的Cookie令牌",
+			method:     http.MethodGet,
+			cookie:     "invalid",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method = ""
+			req := httptest.NewRequest(tt.method, "http://example.com/users/1", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "session", Value: tt.cookie})
+			}
+			w := httptest.NewRecorder()
+			handler(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantMethod, method)
+			if tt.wantStatus != http.StatusOK {
+				// 认证失败与 CSRF 校验失败使用相同的 JSON 错误格式
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
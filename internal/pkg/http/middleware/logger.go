@@ -1,37 +1,146 @@
 package middleware
 
 import (
+	"fmt"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
 	"time"
 
+	"go-api-mono/internal/pkg/core"
 	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/reporting"
 
 	"go.uber.org/zap"
 )
 
+// 访问日志格式
+const (
+	LogFormatJSON     = "json"     // 结构化字段
+	LogFormatCommon   = "common"   // Common Log Format
+	LogFormatCombined = "combined" // Combined Log Format，在 CLF 基础上增加 Referer 与 User-Agent
+)
+
+// clfTimeFormat Common Log Format 的时间格式
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// LoggerOptions 访问日志中间件选项
+type LoggerOptions struct {
+	Format        string        // 日志格式：json、common 或 combined
+	SlowThreshold time.Duration // 处理时间超过该值的请求以 Warn 级别记录，为0时不检测
+	// SampleRate 2xx 响应的记录比例，取值 (0, 1)；<= 0 或 >= 1 时全部记录
+	// 慢请求与非 2xx 响应始终记录
+	SampleRate float64
+}
+
+// DefaultLoggerOptions 默认访问日志选项
+var DefaultLoggerOptions = LoggerOptions{
+	Format:        LogFormatJSON,
+	SlowThreshold: time.Second,
+	SampleRate:    1,
+}
+
 // Logger 日志中间件
 func Logger(log *logger.Logger) Middleware {
+	return LoggerWithOptions(log, DefaultLoggerOptions)
+}
+
+// LoggerWithOptions 使用指定选项创建访问日志中间件
+// 5xx 响应以 Error 级别记录，慢请求以 Warn 级别记录，其余以 Info 级别记录
+func LoggerWithOptions(log *logger.Logger, opts LoggerOptions) Middleware {
 	return func(next HandlerFunc) HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
-			path := r.URL.Path
-			query := r.URL.RawQuery
-			method := r.Method
+			// 内层的认证中间件通过 reporting.SetUser 记录当前用户
+			r = r.WithContext(reporting.WithScope(r.Context()))
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
 
 			defer func() {
 				latency := time.Since(start)
-				requestID, _ := r.Context().Value("request_id").(string)
-				log.Info("HTTP Request",
-					zap.String("method", method),
-					zap.String("path", path),
-					zap.String("query", query),
-					zap.String("ip", r.RemoteAddr),
-					zap.Duration("latency", latency),
-					zap.String("request_id", requestID),
-				)
+				slow := opts.SlowThreshold > 0 && latency > opts.SlowThreshold
+
+				l := log.WithContext(r.Context())
+				logf := l.Info
+				switch {
+				case sw.status >= http.StatusInternalServerError:
+					logf = l.Error
+				case slow:
+					logf = l.Warn
+				case sw.status < http.StatusMultipleChoices && !sampled(opts.SampleRate):
+					return
+				}
+
+				msg, fields := accessLogEntry(r, sw, start, latency, opts.Format)
+				if slow {
+					fields = append(fields, zap.Bool("slow", true))
+				}
+				logf(msg, fields...)
 			}()
 
-			next(w, r)
+			next(sw, r)
+		}
+	}
+}
+
+// sampled 按比例决定是否记录本次请求
+func sampled(rate float64) bool {
+	return rate <= 0 || rate >= 1 || rand.Float64() < rate
+}
+
+// accessLogEntry 按格式生成访问日志的消息与字段
+// CLF 格式将访问记录写入消息，只附带请求ID与慢请求标记等少量字段
+func accessLogEntry(r *http.Request, sw *statusWriter, start time.Time, latency time.Duration, format string) (string, []zap.Field) {
+	userID := ""
+	if user := reporting.UserFromContext(r.Context()); user != nil {
+		userID = user.ID
+	}
+	route := ""
+	if rt := core.CurrentRoute(r.Context()); rt != nil {
+		route = rt.Path
+	}
+	requestID := core.RequestID(r.Context())
+
+	switch format {
+	case LogFormatCommon, LogFormatCombined:
+		msg := fmt.Sprintf(`%s - %s [%s] "%s %s %s" %d %s`,
+			core.ClientIP(r), orDash(userID), start.Format(clfTimeFormat),
+			r.Method, r.URL.RequestURI(), r.Proto, sw.status, clfSize(sw.size))
+		if format == LogFormatCombined {
+			msg += fmt.Sprintf(` %q %q`, orDash(r.Referer()), orDash(r.UserAgent()))
+		}
+		return msg, []zap.Field{
+			zap.String("request_id", requestID),
+			zap.Duration("latency", latency),
 		}
+	default:
+		return "HTTP Request", []zap.Field{
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.String("query", r.URL.RawQuery),
+			zap.String("route", route),
+			zap.Int("status", sw.status),
+			zap.Int64("size", sw.size),
+			zap.String("ip", core.ClientIP(r)),
+			zap.String("user_id", userID),
+			zap.String("user_agent", r.UserAgent()),
+			zap.Duration("latency", latency),
+			zap.String("request_id", requestID),
+		}
+	}
+}
+
+// orDash CLF 中以 "-" 表示缺失的字段
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// clfSize CLF 中的响应大小，未写出响应体时为 "-"
+func clfSize(size int64) string {
+	if size == 0 {
+		return "-"
 	}
+	return strconv.FormatInt(size, 10)
 }
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/reporting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newObservedLogger 创建记录日志条目的日志记录器
+func newObservedLogger() (*logger.Logger, *observer.ObservedLogs) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	return logger.NewFromZap(zap.New(observed)), logs
+}
+
+func TestLoggerJSON(t *testing.T) {
+	log, logs := newObservedLogger()
+	handler := Chain(RequestID(), Logger(log))(func(w http.ResponseWriter, r *http.Request) {
+		reporting.SetUser(r.Context(), reporting.User{ID: "7"})
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users?page=2", nil)
+	req.Header.Set(core.RequestIDHeader, "req-1")
+	req.Header.Set("User-Agent", "test-agent")
+	handler(httptest.NewRecorder(), req)
+
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.InfoLevel, entry.Level)
+	assert.Equal(t, "HTTP Request", entry.Message)
+
+	fields := entry.ContextMap()
+	assert.Equal(t, http.MethodPost, fields["method"])
+	assert.Equal(t, "/users", fields["path"])
+	assert.Equal(t, "page=2", fields["query"])
+	assert.Equal(t, int64(http.StatusCreated), fields["status"])
+	assert.Equal(t, int64(5), fields["size"])
+	assert.Equal(t, "7", fields["user_id"])
+	assert.Equal(t, "test-agent", fields["user_agent"])
+	assert.Equal(t, "req-1", fields["request_id"])
+}
+
+func TestLoggerCLF(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "Common Log Format",
+			format: LogFormatCommon,
+			want:   `^192\.0\.2\.1 - 7 \[[^\]]+\] "GET /users\?page=2 HTTP/1\.1" 200 5$`,
+		},
+		{
+			name:   "Combined Log Format",
+			format: LogFormatCombined,
+			want:   `^192\.0\.2\.1 - 7 \[[^\]]+\] "GET /users\?page=2 HTTP/1\.1" 200 5 "-" "test-agent"$`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, logs := newObservedLogger()
+			opts := DefaultLoggerOptions
+			opts.Format = tt.format
+			handler := LoggerWithOptions(log, opts)(func(w http.ResponseWriter, r *http.Request) {
+				reporting.SetUser(r.Context(), reporting.User{ID: "7"})
+				w.Write([]byte("hello"))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users?page=2", nil)
+			req.Header.Set("User-Agent", "test-agent")
+			handler(httptest.NewRecorder(), req)
+
+			require.Equal(t, 1, logs.Len())
+			assert.Regexp(t, regexp.MustCompile(tt.want), logs.All()[0].Message)
+		})
+	}
+}
+
+func TestLoggerLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		delay     time.Duration
+		wantLevel zapcore.Level
+		wantSlow  bool
+	}{
+		{name: "成功响应", status: http.StatusOK, wantLevel: zapcore.InfoLevel},
+		{name: "客户端错误", status: http.StatusNotFound, wantLevel: zapcore.InfoLevel},
+		{name: "服务端错误", status: http.StatusBadGateway, wantLevel: zapcore.ErrorLevel},
+		{name: "慢请求", status: http.StatusOK, delay: 20 * time.Millisecond, wantLevel: zapcore.WarnLevel, wantSlow: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, logs := newObservedLogger()
+			opts := DefaultLoggerOptions
+			opts.SlowThreshold = 10 * time.Millisecond
+			handler := LoggerWithOptions(log, opts)(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.delay)
+				w.WriteHeader(tt.status)
+			})
+			handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			require.Equal(t, 1, logs.Len())
+			entry := logs.All()[0]
+			assert.Equal(t, tt.wantLevel, entry.Level)
+			_, slow := entry.ContextMap()["slow"]
+			assert.Equal(t, tt.wantSlow, slow)
+		})
+	}
+}
+
+func TestLoggerSampling(t *testing.T) {
+	log, logs := newObservedLogger()
+	opts := DefaultLoggerOptions
+	opts.SampleRate = 0.0001
+	status := http.StatusOK
+	handler := LoggerWithOptions(log, opts)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	})
+
+	for i := 0; i < 100; i++ {
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.Less(t, logs.Len(), 5, "2xx 响应按比例采样")
+
+	// 非 2xx 响应始终记录
+	before := logs.Len()
+	status = http.StatusNotFound
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, before+1, logs.Len())
+}
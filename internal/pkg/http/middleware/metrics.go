@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go-api-mono/internal/pkg/metrics"
+)
+
+// Metrics 指标中间件，按路由模式记录请求数、延迟与并发数
+// 应作为最外层的中间件，使恢复的 panic 与其他中间件的拒绝也被统计
+func Metrics(m *metrics.Metrics) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			done := m.StartRequest(r.Method, metrics.RouteLabel(r))
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				done(sw.status)
+			}()
+
+			next(sw, r)
+		}
+	}
+}
+
+// statusWriter 记录响应状态码与写出的字节数
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.size += int64(n)
+	return n, err
+}
+
+// Unwrap 返回底层的 ResponseWriter，供 http.ResponseController 使用
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
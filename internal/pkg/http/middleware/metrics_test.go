@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/metrics"
+	"go-api-mono/internal/pkg/security"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+	log := logger.NewNop()
+
+	srv := core.NewServer(core.ServerOptions{Logger: log})
+	srv.Use(Core(
+		Metrics(m),
+		RecoveryWithOptions(log, RecoveryOptions{
+			OnPanic: func(r *http.Request, _ interface{}) { m.PanicRecovered(r) },
+		}),
+	))
+	g := srv.Group("/users")
+	g.Handle("GET", "/{id}", func(c *core.Context) {
+		c.Response.Success(nil)
+	})
+	g.Handle("GET", "/{id}/panic", func(c *core.Context) {
+		panic("boom")
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/3/panic"} {
+		srv.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	for _, method := range []string{"FOO", "BAR", "get"} {
+		srv.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/users/1", nil))
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+
+	// 标签使用路由模式而非原始路径
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/users/{id}",status="200"} 2`)
+	assert.Contains(t, body, `http_requests_total{method="GET",route="/users/{id}/panic",status="500"} 1`)
+	assert.Contains(t, body, `http_panics_recovered_total{route="/users/{id}/panic"} 1`)
+	assert.Contains(t, body, `http_requests_in_flight{method="GET",route="/users/{id}"} 0`)
+	assert.Contains(t, body, "go_goroutines")
+	assert.NotContains(t, body, "/users/1")
+	// 非标准方法合并为同一个标签值
+	assert.Contains(t, body, `http_requests_total{method="OTHER",route="unmatched",status="405"} 3`)
+	assert.NotContains(t, body, `method="FOO"`)
+}
+
+func TestRateLimitOnLimited(t *testing.T) {
+	m := metrics.New()
+	opts := DefaultRateLimitOptions
+	opts.OnLimited = m.RateLimited
+
+	handler := RateLimit(security.NewMemoryRateLimiter(security.AlgorithmTokenBucket, security.MemoryOptions{}), security.Limit{Rate: 1, Burst: 1}, opts)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for i := 0; i < 3; i++ {
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	}
+
+	expected := `
+# HELP http_rate_limited_total Total number of requests rejected by the rate limiter.
+# TYPE http_rate_limited_total counter
+http_rate_limited_total{route="unmatched"} 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(m.Registry(), strings.NewReader(expected), "http_rate_limited_total"))
+}
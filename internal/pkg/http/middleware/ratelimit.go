@@ -1,9 +1,13 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"go-api-mono/internal/pkg/core"
 	"go-api-mono/internal/pkg/errors"
 	"go-api-mono/internal/pkg/security"
 )
@@ -12,63 +16,89 @@ import (
 type RateLimitOptions struct {
 	SkipPaths []string                   // 跳过限流的路径
 	GetIPKey  func(*http.Request) string // 自定义获取IP的函数
+	OnLimited func(*http.Request)        // 请求被拒绝时的回调，如记录指标
+	OnError   func(*http.Request, error) // 限流后端出错时的回调，出错的请求照常放行
 }
 
 // DefaultRateLimitOptions 默认速率限制选项
 var DefaultRateLimitOptions = RateLimitOptions{
-	SkipPaths: []string{"/health", "/metrics"},
-	GetIPKey: func(r *http.Request) string {
-		// 默认从X-Real-IP或X-Forwarded-For获取IP
-		if ip := r.Header.Get("X-Real-IP"); ip != "" {
-			return ip
-		}
-		if ip := r.Header.Get("X-Forwarded-For"); ip != "" {
-			i := strings.Index(ip, ",")
-			if i == -1 {
-				return ip
-			}
-			return ip[:i]
-		}
-		// 从RemoteAddr获取
-		ip := r.RemoteAddr
-		i := strings.LastIndex(ip, ":")
-		if i == -1 {
-			return ip
-		}
-		return ip[:i]
-	},
+	SkipPaths: []string{"/healthz", "/readyz", "/metrics"},
+	GetIPKey:  core.ClientIP, // 默认使用经受信任代理解析后的客户端IP
 }
 
 // RateLimit 创建速率限制中间件
-func RateLimit(limiter *security.IPRateLimiter, opts RateLimitOptions) Middleware {
+// 响应携带 IETF RateLimit-Limit、RateLimit-Remaining 与 RateLimit-Reset 响应头，
+// 被拒绝的请求返回 429 与 Retry-After
+func RateLimit(limiter security.RateLimiter, limit security.Limit, opts RateLimitOptions) Middleware {
 	return func(next HandlerFunc) HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			// 检查是否需要跳过限流
-			for _, path := range opts.SkipPaths {
-				if strings.HasPrefix(r.URL.Path, path) {
-					next(w, r)
-					return
-				}
+			if skipRateLimit(r, opts) {
+				next(w, r)
+				return
 			}
 
 			// 获取客户端标识（默认使用IP）
 			key := opts.GetIPKey(r)
+			enforceRateLimit(w, r, next, limiter, key, limit, 1, opts)
+		}
+	}
+}
 
-			// 获取限流器并检查是否允许请求
-			if !limiter.GetLimiter(key).Allow() {
-				http.Error(w, errors.ErrTooManyRequests.Error(), http.StatusTooManyRequests)
-				return
-			}
+// skipRateLimit 判断请求路径是否跳过限流
+func skipRateLimit(r *http.Request, opts RateLimitOptions) bool {
+	for _, path := range opts.SkipPaths {
+		if strings.HasPrefix(r.URL.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// enforceRateLimit 消耗键的配额，配额不足时返回 429，否则调用后续处理器
+func enforceRateLimit(w http.ResponseWriter, r *http.Request, next HandlerFunc,
+	limiter security.RateLimiter, key string, limit security.Limit, cost int, opts RateLimitOptions) {
+	res, err := limiter.Allow(r.Context(), key, limit, cost)
+	if err != nil {
+		// 限流后端不可用时放行，避免限流器成为单点故障
+		if opts.OnError != nil {
+			opts.OnError(r, err)
+		}
+		next(w, r)
+		return
+	}
 
-			next(w, r)
+	setRateLimitHeaders(w.Header(), res)
+	if !res.Allowed {
+		if opts.OnLimited != nil {
+			opts.OnLimited(r)
 		}
+		retryAfter := ceilSeconds(res.RetryAfter)
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		writeError(w, r, errors.New(errors.ErrCodeTooManyRequests, "Too many requests").
+			WithDetails(map[string]int{"retry_after": retryAfter}))
+		return
 	}
+
+	next(w, r)
+}
+
+// setRateLimitHeaders 设置 IETF RateLimit 响应头
+func setRateLimitHeaders(header http.Header, res security.Result) {
+	header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+}
+
+// ceilSeconds 将时长向上取整为秒，正的时长至少为1秒
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
 
 // IPRateLimit 创建基于IP的速率限制中间件
 func IPRateLimit(requests, burst int) Middleware {
-	limiter := security.NewIPRateLimiter(float64(requests), float64(burst))
-	opts := DefaultRateLimitOptions
+	limiter := security.NewMemoryRateLimiter(security.AlgorithmTokenBucket, security.DefaultMemoryOptions)
+	limit := security.Limit{Rate: float64(requests), Burst: burst}
 
-	return RateLimit(limiter, opts)
+	return RateLimit(limiter, limit, DefaultRateLimitOptions)
 }
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"go-api-mono/internal/pkg/auth"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/security"
+)
+
+// 限流键的组成部分
+const (
+	RateLimitKeyIP     = "ip"     // 客户端IP
+	RateLimitKeyUser   = "user"   // JWT 中的用户ID
+	RateLimitKeyAPIKey = "apiKey" // 已认证的 API Key
+)
+
+// DefaultRateLimitPolicy 未绑定策略的路由使用的策略名称
+const DefaultRateLimitPolicy = "default"
+
+// RateLimitPolicy 命名的限流策略
+type RateLimitPolicy struct {
+	Name  string
+	Limit security.Limit
+	// KeyBy 限流键的组成，如 ["user"] 或 ["apiKey", "ip"]；
+	// 请求中缺少用户或 API Key 时以客户端IP代替，为空时按IP限流
+	KeyBy []string
+	// Roles 按 JWT 中的角色覆盖配额，如为 premium 角色提供更高的配额
+	Roles map[string]security.Limit
+}
+
+// RateLimitRoute 路由与限流策略的绑定
+type RateLimitRoute struct {
+	// Route 路由模式，可带请求方法，如 "GET /api/v1/users"；
+	// 省略版本段（如 "/api/users/{id}"）时匹配所有版本
+	Route  string
+	Policy string
+	Cost   int // 每个请求消耗的配额，为0时消耗1
+}
+
+// rateLimitBinding 路由绑定的策略与消耗
+type rateLimitBinding struct {
+	policy *RateLimitPolicy
+	cost   int
+}
+
+// rateLimitRuleSet 编译后的限流规则
+type rateLimitRuleSet struct {
+	fallback *RateLimitPolicy
+	routes   map[string]rateLimitBinding
+}
+
+// RateLimitRules 可热更新的限流规则
+type RateLimitRules struct {
+	current atomic.Pointer[rateLimitRuleSet]
+}
+
+// NewRateLimitRules 创建限流规则，policies 中必须包含名为 default 的策略
+func NewRateLimitRules(policies []RateLimitPolicy, routes []RateLimitRoute) (*RateLimitRules, error) {
+	rules := &RateLimitRules{}
+	if err := rules.Update(policies, routes); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Update 校验并原子地替换限流规则，正在处理的请求不受影响
+func (r *RateLimitRules) Update(policies []RateLimitPolicy, routes []RateLimitRoute) error {
+	set := &rateLimitRuleSet{routes: make(map[string]rateLimitBinding)}
+	byName := make(map[string]*RateLimitPolicy, len(policies))
+	for i := range policies {
+		policy := policies[i]
+		for _, part := range policy.KeyBy {
+			switch part {
+			case RateLimitKeyIP, RateLimitKeyUser, RateLimitKeyAPIKey:
+			default:
+				return fmt.Errorf("rate limit policy %s: unknown key %q", policy.Name, part)
+			}
+		}
+		byName[policy.Name] = &policy
+	}
+
+	set.fallback = byName[DefaultRateLimitPolicy]
+	if set.fallback == nil {
+		return fmt.Errorf("rate limit policy %q is required", DefaultRateLimitPolicy)
+	}
+
+	for _, route := range routes {
+		policy := byName[route.Policy]
+		if policy == nil {
+			return fmt.Errorf("rate limit route %s: unknown policy %q", route.Route, route.Policy)
+		}
+		cost := max(route.Cost, 1)
+		set.routes[normalizeRoute(route.Route)] = rateLimitBinding{policy: policy, cost: cost}
+	}
+
+	r.current.Store(set)
+	return nil
+}
+
+// resolve 返回请求所在路由绑定的策略与消耗
+func (r *RateLimitRules) resolve(req *http.Request) (*RateLimitPolicy, int) {
+	return r.lookup(core.CurrentRoute(req.Context()))
+}
+
+// Policy 返回路由绑定的限流策略名称，未绑定时为 default
+func (r *RateLimitRules) Policy(route *core.Route) string {
+	policy, _ := r.lookup(route)
+	return policy.Name
+}
+
+// lookup 返回路由绑定的策略与消耗
+func (r *RateLimitRules) lookup(route *core.Route) (*RateLimitPolicy, int) {
+	set := r.current.Load()
+	for _, pattern := range routePatterns(route) {
+		if binding, ok := set.routes[pattern]; ok {
+			return binding.policy, binding.cost
+		}
+	}
+	return set.fallback, 1
+}
+
+// routePatterns 返回可匹配路由的模式，按优先顺序排列
+// 依次为带方法的完整路径、完整路径、带方法的无版本路径与无版本路径
+func routePatterns(route *core.Route) []string {
+	if route == nil {
+		return nil
+	}
+	paths := []string{route.Path}
+	if route.Version != nil {
+		paths = append(paths, strings.Replace(route.Path, "/"+route.Version.Name, "", 1))
+	}
+	patterns := make([]string, 0, 2*len(paths))
+	for _, path := range paths {
+		patterns = append(patterns, route.Method+" "+path, path)
+	}
+	return patterns
+}
+
+// normalizeRoute 规范化路由模式中方法与路径之间的空白
+func normalizeRoute(route string) string {
+	if method, path, found := strings.Cut(strings.TrimSpace(route), " "); found {
+		return strings.ToUpper(method) + " " + strings.TrimSpace(path)
+	}
+	return strings.TrimSpace(route)
+}
+
+// PolicyRateLimit 按路由绑定的策略限流
+// 路由未绑定策略时使用 default 策略，不同策略的计数相互独立
+func PolicyRateLimit(limiter security.RateLimiter, rules *RateLimitRules, opts RateLimitOptions) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if skipRateLimit(r, opts) {
+				next(w, r)
+				return
+			}
+
+			policy, cost := rules.resolve(r)
+			claims, _ := r.Context().Value(ClaimsKey).(*auth.Claims)
+
+			limit := policy.Limit
+			if claims != nil {
+				if roleLimit, ok := policy.Roles[claims.Role]; ok {
+					limit = roleLimit
+				}
+			}
+
+			key := policy.Name + ":" + rateLimitKey(r, policy.KeyBy, claims, opts)
+			enforceRateLimit(w, r, next, limiter, key, limit, cost, opts)
+		}
+	}
+}
+
+// rateLimitKey 按策略组合限流键，API Key 以哈希形式出现在键中
+// API Key 只取自 APIKey 中间件验证后写入上下文的取值：未经验证的请求头可以随意更换，按其计数会绕过限流
+func rateLimitKey(r *http.Request, keyBy []string, claims *auth.Claims, opts RateLimitOptions) string {
+	if len(keyBy) == 0 {
+		keyBy = []string{RateLimitKeyIP}
+	}
+
+	parts := make([]string, 0, len(keyBy))
+	for _, part := range keyBy {
+		switch part {
+		case RateLimitKeyUser:
+			if claims != nil {
+				parts = append(parts, fmt.Sprintf("user=%d", claims.UserID))
+				continue
+			}
+		case RateLimitKeyAPIKey:
+			if apiKey, _ := r.Context().Value(APIKeyKey).(string); apiKey != "" {
+				sum := sha256.Sum256([]byte(apiKey))
+				parts = append(parts, "key="+hex.EncodeToString(sum[:8]))
+				continue
+			}
+		}
+		if ip := "ip=" + opts.GetIPKey(r); !slices.Contains(parts, ip) {
+			parts = append(parts, ip)
+		}
+	}
+	return strings.Join(parts, ",")
+}
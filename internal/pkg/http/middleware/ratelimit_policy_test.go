@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"go-api-mono/internal/pkg/auth"
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/security"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withTestClaims 根据 X-User 与 X-Role 请求头模拟 JWT 中间件写入的声明
+func withTestClaims() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if user := r.Header.Get("X-User"); user != "" {
+				id, _ := strconv.Atoi(user)
+				claims := &auth.Claims{UserID: uint(id), Role: r.Header.Get("X-Role")}
+				r = r.WithContext(context.WithValue(r.Context(), ClaimsKey, claims))
+			}
+			next(w, r)
+		}
+	}
+}
+
+func TestPolicyRateLimit(t *testing.T) {
+	policies := []RateLimitPolicy{
+		{Name: DefaultRateLimitPolicy, Limit: security.Limit{Rate: 0.001, Burst: 3}},
+		{
+			Name:  "users",
+			Limit: security.Limit{Rate: 0.001, Burst: 10},
+			KeyBy: []string{RateLimitKeyUser},
+			Roles: map[string]security.Limit{"premium": {Rate: 0.001, Burst: 20}},
+		},
+		{Name: "partners", Limit: security.Limit{Rate: 0.001, Burst: 2}, KeyBy: []string{RateLimitKeyAPIKey}},
+	}
+	routes := []RateLimitRoute{
+		{Route: "GET /api/users", Policy: "users", Cost: 5},
+		{Route: "/api/v1/users/{id}", Policy: "users"},
+		{Route: "get /api/v1/partners", Policy: "partners"},
+	}
+	rules, err := NewRateLimitRules(policies, routes)
+	require.NoError(t, err)
+
+	srv := core.NewServer(core.ServerOptions{Logger: logger.NewNop()})
+	v1 := srv.API("/api", core.VersioningOptions{Default: "v1"}).Version("v1")
+	v1.Use(Core(withTestClaims(), APIKey([]string{"key-a", "key-b"}, DefaultAPIKeyOptions), PolicyRateLimit(security.NewMemoryRateLimiter(security.AlgorithmGCRA, security.MemoryOptions{}), rules, DefaultRateLimitOptions)))
+	ok := func(c *core.Context) { c.Response.Success(nil) }
+	v1.Handle("GET", "/users", ok)
+	v1.Handle("GET", "/users/{id}", ok)
+	v1.Handle("GET", "/partners", ok)
+	v1.Handle("GET", "/status", ok)
+
+	request := func(path string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w
+	}
+	allowed := func(path string, header map[string]string) int {
+		n := 0
+		for i := 0; i < 30; i++ {
+			if request(path, header).Code == http.StatusOK {
+				n++
+			}
+		}
+		return n
+	}
+
+	t.Run("列表路由消耗更多配额", func(t *testing.T) {
+		assert.Equal(t, 2, allowed("/api/v1/users", map[string]string{"X-User": "1"}))
+		w := request("/api/v1/users/1", map[string]string{"X-User": "1"})
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "10", w.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("按用户独立计数", func(t *testing.T) {
+		assert.Equal(t, 10, allowed("/api/v1/users/2", map[string]string{"X-User": "2"}))
+	})
+
+	t.Run("高级角色使用更高的配额", func(t *testing.T) {
+		assert.Equal(t, 20, allowed("/api/v1/users/3", map[string]string{"X-User": "3", "X-Role": "premium"}))
+	})
+
+	t.Run("不带版本的路径使用相同策略", func(t *testing.T) {
+		assert.Equal(t, 10, allowed("/api/users/4", map[string]string{"X-User": "4"}))
+	})
+
+	t.Run("按API Key计数", func(t *testing.T) {
+		assert.Equal(t, 2, allowed("/api/v1/partners", map[string]string{"X-API-Key": "key-a"}))
+		assert.Equal(t, 2, allowed("/api/v1/partners", map[string]string{"X-API-Key": "key-b"}))
+	})
+
+	t.Run("更换未知的API Key不能绕过限流", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			w := request("/api/v1/partners", map[string]string{"X-API-Key": "rotated-" + strconv.Itoa(i)})
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		}
+		// 未携带 API Key 时按IP计数
+		assert.Equal(t, 2, allowed("/api/v1/partners", nil))
+	})
+
+	t.Run("未绑定的路由使用默认策略", func(t *testing.T) {
+		assert.Equal(t, 3, allowed("/api/v1/status", nil))
+	})
+
+	t.Run("热更新规则", func(t *testing.T) {
+		// 无效的规则不会替换当前规则
+		assert.Error(t, rules.Update(policies, []RateLimitRoute{{Route: "/api/users/{id}", Policy: "missing"}}))
+		assert.Error(t, rules.Update(policies[1:], nil))
+
+		err := rules.Update(policies, []RateLimitRoute{{Route: "/api/users/{id}", Policy: "users", Cost: 10}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, allowed("/api/v1/users/5", map[string]string{"X-User": "5"}))
+	})
+}
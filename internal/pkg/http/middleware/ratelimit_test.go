@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-mono/internal/pkg/security"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingLimiter 总是返回错误的限流后端
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, security.Limit, int) (security.Result, error) {
+	return security.Result{}, fmt.Errorf("connection refused")
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := security.NewMemoryRateLimiter(security.AlgorithmGCRA, security.MemoryOptions{})
+	handler := RateLimit(limiter, security.Limit{Rate: 1, Burst: 2}, DefaultRateLimitOptions)(
+		func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	request := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+		return w
+	}
+
+	w := request()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Reset"))
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, request().Code)
+
+	// 超出配额返回 JSON 格式的 429
+	w = request()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	var body struct {
+		Details map[string]int `json:"details"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1, body.Details["retry_after"])
+
+	// 跳过的路径不限流也不输出响应头
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimitBackendError(t *testing.T) {
+	var failed error
+	opts := DefaultRateLimitOptions
+	opts.OnError = func(_ *http.Request, err error) { failed = err }
+
+	handler := RateLimit(failingLimiter{}, security.Limit{Rate: 1, Burst: 1}, opts)(
+		func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	// 后端不可用时放行请求
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Error(t, failed)
+}
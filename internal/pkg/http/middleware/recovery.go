@@ -1,32 +1,59 @@
 package middleware
 
 import (
-	"fmt"
 	"net/http"
-	"runtime/debug"
 
 	"go-api-mono/internal/pkg/errors"
 	"go-api-mono/internal/pkg/logger"
-
-	"go.uber.org/zap"
+	"go-api-mono/internal/pkg/reporting"
 )
 
+// RecoveryOptions 恢复中间件选项
+type RecoveryOptions struct {
+	OnPanic  func(r *http.Request, err interface{}) // panic 被恢复后的回调，如记录指标
+	Reporter reporting.Reporter                     // 错误上报器，为空时仅记录日志
+}
+
 // Recovery 恢复中间件
 func Recovery(log *logger.Logger) Middleware {
+	return RecoveryWithOptions(log, RecoveryOptions{})
+}
+
+// RecoveryWithOptions 使用指定选项创建恢复中间件
+// 恢复的 panic 连同调用栈、请求信息与当前用户交给上报器，并返回 500 JSON 错误；
+// 若响应已开始写出则无法再返回错误，改为中断连接，避免客户端收到看似完整的响应
+func RecoveryWithOptions(log *logger.Logger, opts RecoveryOptions) Middleware {
+	reporter := opts.Reporter
+	if reporter == nil {
+		reporter = reporting.NewLogReporter(log)
+	}
+
 	return func(next HandlerFunc) HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
+			// 内层的认证中间件通过 reporting.SetUser 记录当前用户
+			r = r.WithContext(reporting.WithScope(r.Context()))
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
 			defer func() {
-				if err := recover(); err != nil {
-					requestID, _ := r.Context().Value("request_id").(string)
-					log.Error("Panic recovered",
-						zap.String("error", fmt.Sprint(err)),
-						zap.String("request_id", requestID),
-						zap.String("stack", string(debug.Stack())),
-					)
-					http.Error(w, errors.ErrInternal.Error(), http.StatusInternalServerError)
+				err := recover()
+				if err == nil {
+					return
+				}
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
+				reporter.Report(r.Context(), reporting.NewEvent(r, err))
+				if opts.OnPanic != nil {
+					opts.OnPanic(r, reporting.Unwrap(err))
+				}
+
+				if sw.wroteHeader {
+					panic(http.ErrAbortHandler)
 				}
+				writeError(w, r, errors.New(errors.ErrCodeInternal, "Internal server error"))
 			}()
-			next(w, r)
+			next(sw, r)
 		}
 	}
 }
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/errors"
+	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/reporting"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingReporter 记录收到的事件
+type recordingReporter struct {
+	events []*reporting.Event
+}
+
+func (r *recordingReporter) Report(_ context.Context, event *reporting.Event) {
+	r.events = append(r.events, event)
+}
+
+func TestRecovery(t *testing.T) {
+	reporter := &recordingReporter{}
+	var panics int
+	recovery := RecoveryWithOptions(logger.NewNop(), RecoveryOptions{
+		Reporter: reporter,
+		OnPanic:  func(*http.Request, interface{}) { panics++ },
+	})
+
+	t.Run("返回 JSON 错误并上报", func(t *testing.T) {
+		handler := Chain(RequestID(), recovery)(func(w http.ResponseWriter, r *http.Request) {
+			reporting.SetUser(r.Context(), reporting.User{ID: "7"})
+			panic("boom")
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/users/1?token=secret", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set(core.RequestIDHeader, "req-1")
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var body core.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, int(errors.ErrCodeInternal), body.Code)
+		assert.Equal(t, "req-1", body.RequestID)
+		assert.NotContains(t, w.Body.String(), "boom")
+
+		require.Len(t, reporter.events, 1)
+		event := reporter.events[0]
+		assert.Equal(t, "boom", event.Message)
+		assert.Equal(t, "req-1", event.Request.RequestID)
+		assert.Equal(t, reporting.Filtered, event.Request.Headers["Authorization"])
+		assert.NotContains(t, event.Request.URL, "secret")
+		require.NotNil(t, event.User)
+		assert.Equal(t, "7", event.User.ID)
+		assert.NotEmpty(t, event.Stack)
+		assert.Equal(t, 1, panics)
+	})
+
+	t.Run("响应已开始写出时中断连接", func(t *testing.T) {
+		handler := recovery(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"partial":`))
+			panic("boom")
+		})
+
+		w := httptest.NewRecorder()
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+		assert.Equal(t, `{"partial":`, w.Body.String())
+		assert.Len(t, reporter.events, 2)
+	})
+
+	t.Run("ErrAbortHandler 不上报", func(t *testing.T) {
+		handler := recovery(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})
+		assert.Panics(t, func() {
+			handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+		assert.Len(t, reporter.events, 2)
+	})
+}
+
+// panicHandler 引发 panic 的处理器，用于检查上报的调用栈
+func panicHandler(w http.ResponseWriter, r *http.Request) {
+	panic("boom")
+}
+
+func TestRecoveryTimeout(t *testing.T) {
+	reporter := &recordingReporter{}
+	var recovered interface{}
+	recovery := RecoveryWithOptions(logger.NewNop(), RecoveryOptions{
+		Reporter: reporter,
+		OnPanic:  func(_ *http.Request, err interface{}) { recovered = err },
+	})
+
+	t.Run("上报处理器协程中的原始 panic", func(t *testing.T) {
+		handler := Chain(recovery, Timeout(time.Second))(panicHandler)
+
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "boom", recovered)
+		require.Len(t, reporter.events, 1)
+		event := reporter.events[0]
+		assert.Equal(t, "boom", event.Message)
+		require.NotEmpty(t, event.Stack)
+		assert.True(t, strings.HasSuffix(event.Stack[0].Function, "middleware.panicHandler"), event.Stack[0].Function)
+		// 调用栈来自处理器协程，而不是重新抛出 panic 的请求协程
+		assert.NotContains(t, reporting.FormatStack(event.Stack), "TestRecoveryTimeout")
+	})
+
+	t.Run("ErrAbortHandler 原样传递", func(t *testing.T) {
+		handler := Chain(recovery, Timeout(time.Second))(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})
+
+		w := httptest.NewRecorder()
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+		assert.Len(t, reporter.events, 1)
+	})
+}
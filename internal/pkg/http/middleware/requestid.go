@@ -1,24 +1,23 @@
 package middleware
 
 import (
-	"context"
 	"net/http"
 
+	"go-api-mono/internal/pkg/core"
 	"go-api-mono/internal/pkg/utils"
 )
 
 // RequestID 请求ID中间件
+// 请求ID写入上下文，可通过 core.RequestID 读取，并随出站请求传递给下游服务
 func RequestID() Middleware {
 	return func(next HandlerFunc) HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
-			requestID := r.Header.Get("X-Request-ID")
+			requestID := r.Header.Get(core.RequestIDHeader)
 			if requestID == "" {
 				requestID = utils.GenerateRequestID()
 			}
-			w.Header().Set("X-Request-ID", requestID)
-			ctx := r.Context()
-			ctx = context.WithValue(ctx, RequestIDKey, requestID)
-			next(w, r.WithContext(ctx))
+			w.Header().Set(core.RequestIDHeader, requestID)
+			next(w, r.WithContext(core.WithRequestID(r.Context(), requestID)))
 		}
 	}
 }
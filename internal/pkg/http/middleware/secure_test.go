@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecureHeaders(t *testing.T) {
+	t.Run("按运行模式设置默认值", func(t *testing.T) {
+		tests := []struct {
+			mode     string
+			wantHSTS string
+			wantCORP string
+		}{
+			{"production", "max-age=31536000; includeSubDomains", "same-origin"},
+			{"development", "", "cross-origin"},
+		}
+
+		for _, tt := range tests {
+			handler := SecureHeaders(DefaultSecureHeadersOptions(tt.mode))(func(w http.ResponseWriter, r *http.Request) {})
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tt.wantHSTS, w.Header().Get("Strict-Transport-Security"), tt.mode)
+			assert.Equal(t, tt.wantCORP, w.Header().Get("Cross-Origin-Resource-Policy"), tt.mode)
+			assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"), tt.mode)
+			assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"), tt.mode)
+			assert.Contains(t, w.Header().Get("Content-Security-Policy"), "frame-ancestors 'none'", tt.mode)
+		}
+	})
+
+	t.Run("路由级覆盖与nonce", func(t *testing.T) {
+		global := SecureHeaders(DefaultSecureHeadersOptions("production"))
+		route := SecureHeaders(SecureHeadersOptions{
+			ContentSecurityPolicy:     "script-src 'nonce-{nonce}'",
+			CrossOriginEmbedderPolicy: HeaderOff,
+		})
+
+		var nonce string
+		handler := Chain(global, route)(func(w http.ResponseWriter, r *http.Request) {
+			nonce = CSPNonce(r.Context())
+		})
+
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+		assert.NotEmpty(t, nonce)
+		assert.Equal(t, "script-src 'nonce-"+nonce+"'", w.Header().Get("Content-Security-Policy"))
+		assert.Empty(t, w.Header().Get("Cross-Origin-Embedder-Policy"))
+		// 未覆盖的响应头保持全局设置
+		assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+
+		// 每个请求生成不同的 nonce
+		first := nonce
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/docs", nil))
+		assert.NotEqual(t, first, nonce)
+		assert.False(t, strings.Contains(w.Header().Get("Content-Security-Policy"), noncePlaceholder))
+	})
+}
+
+func TestSecureHeadersOptionsMerge(t *testing.T) {
+	base := DefaultSecureHeadersOptions("production")
+	merged := base.Merge(SecureHeadersOptions{FrameOptions: "SAMEORIGIN", StrictTransportSecurity: HeaderOff})
+
+	assert.Equal(t, "SAMEORIGIN", merged.FrameOptions)
+	assert.Equal(t, HeaderOff, merged.StrictTransportSecurity)
+	assert.Equal(t, base.ContentSecurityPolicy, merged.ContentSecurityPolicy)
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-api-mono/internal/pkg/errors"
+	"go-api-mono/internal/pkg/reporting"
+)
+
+// Timeout 创建处理器超时中间件
+// 超时后取消请求上下文；若处理器尚未写出响应头，则返回 503 JSON 错误，
+// 否则等待处理器自行结束，避免与已开始的响应发生竞争。timeout <= 0 表示不限制
+func Timeout(timeout time.Duration) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if timeout <= 0 {
+				next(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tw := &timeoutWriter{w: w, h: make(http.Header)}
+			done := make(chan struct{})
+			panicChan := make(chan interface{}, 1)
+
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						// 保留原始值与处理器协程的调用栈，ErrAbortHandler 原样传递以便中断连接
+						if p != http.ErrAbortHandler {
+							p = reporting.NewPanic(p)
+						}
+						panicChan <- p
+						return
+					}
+					close(done)
+				}()
+				next(tw, r)
+			}()
+
+			select {
+			case p := <-panicChan:
+				// 在当前协程重新抛出，交由 Recovery 中间件处理
+				panic(p)
+			case <-done:
+			case <-ctx.Done():
+				tw.mu.Lock()
+				if !tw.wroteHeader {
+					tw.timedOut = true
+					tw.mu.Unlock()
+					writeError(w, r, errors.New(errors.ErrCodeTimeout, "request timeout").
+						WithDetails(map[string]interface{}{"timeout": timeout.String()}))
+					return
+				}
+				tw.mu.Unlock()
+
+				// 响应已开始写出，只能等待处理器结束
+				select {
+				case p := <-panicChan:
+					panic(p)
+				case <-done:
+				}
+			}
+		}
+	}
+}
+
+// timeoutWriter 在超时与处理器写入之间做同步
+type timeoutWriter struct {
+	w           http.ResponseWriter
+	h           http.Header
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+// Header 实现 http.ResponseWriter 接口
+// 处理器只修改独立的头部副本，写出时再合并，避免与超时响应并发修改
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.h
+}
+
+// WriteHeader 实现 http.ResponseWriter 接口
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeaderLocked(code)
+}
+
+// Write 实现 http.ResponseWriter 接口
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.writeHeaderLocked(http.StatusOK)
+	return tw.w.Write(b)
+}
+
+// Flush 实现 http.Flusher 接口
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.writeHeaderLocked(http.StatusOK)
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writeHeaderLocked 合并头部并写出状态码，调用方需持有锁
+func (tw *timeoutWriter) writeHeaderLocked(code int) {
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	dst := tw.w.Header()
+	for k, vv := range tw.h {
+		dst[k] = vv
+	}
+	tw.w.WriteHeader(code)
+}
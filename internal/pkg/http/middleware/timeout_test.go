@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	t.Run("处理器未写出响应时返回503", func(t *testing.T) {
+		canceled := make(chan struct{})
+		handler := Timeout(20 * time.Millisecond)(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			close(canceled)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		<-canceled
+	})
+
+	t.Run("响应已开始时等待处理器结束", func(t *testing.T) {
+		handler := Timeout(20 * time.Millisecond)(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Test", "1")
+			w.WriteHeader(http.StatusAccepted)
+			<-r.Context().Done()
+			_, _ = w.Write([]byte("done"))
+		})
+
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "1", w.Header().Get("X-Test"))
+		assert.Equal(t, "done", w.Body.String())
+	})
+
+	t.Run("处理器的panic传递到调用方", func(t *testing.T) {
+		handler := Timeout(time.Second)(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})
+
+		assert.Panics(t, func() {
+			handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		})
+	})
+}
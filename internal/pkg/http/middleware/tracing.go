@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader 响应中携带 trace id 的请求头
+const TraceIDHeader = "X-Trace-ID"
+
+// Tracing 链路追踪中间件
+// 请求携带 traceparent 时延续上游链路，否则开启新的链路
+func Tracing() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			// span 名称使用路由模式，避免每个路径参数产生不同的名称
+			route := r.URL.Path
+			if rt := core.CurrentRoute(ctx); rt != nil {
+				route = rt.Path
+			}
+
+			ctx, span := tracing.Start(ctx, r.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(r.URL.Path),
+					semconv.UserAgentOriginal(r.UserAgent()),
+				),
+			)
+			defer span.End()
+
+			if traceID := tracing.TraceID(ctx); traceID != "" {
+				w.Header().Set(TraceIDHeader, traceID)
+			}
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next(sw, r.WithContext(ctx))
+
+			span.SetAttributes(semconv.HTTPResponseStatusCode(sw.status))
+			if sw.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(sw.status))
+			}
+		}
+	}
+}
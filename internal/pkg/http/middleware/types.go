@@ -4,10 +4,12 @@ package middleware
 type ContextKey string
 
 const (
-	// RequestIDKey 请求ID的上下文键
-	RequestIDKey ContextKey = "request_id"
 	// ClaimsKey JWT声明的上下文键
 	ClaimsKey ContextKey = "claims"
 	// UserKey 用户信息的上下文键
 	UserKey ContextKey = "user"
+	// AuthMethodKey 认证方式的上下文键，取值为 auth.ModeBearer 或 auth.ModeCookie
+	AuthMethodKey ContextKey = "auth_method"
+	// APIKeyKey 已认证的 API Key 的上下文键，由 APIKey 中间件写入，取值为 string
+	APIKeyKey ContextKey = "api_key"
 )
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/errors"
+	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/openapi"
+
+	"go.uber.org/zap"
+)
+
+// ValidationOptions 请求校验中间件选项
+type ValidationOptions struct {
+	ValidateResponses bool           // 是否校验响应（建议仅在测试环境开启）
+	Logger            *logger.Logger // 记录响应校验失败的日志记录器
+}
+
+// Validation 创建基于 OpenAPI 文档的请求校验中间件
+// 依赖 core 路由注入的当前路由来定位文档中的操作，未在文档中描述的路由直接放行
+func Validation(v *openapi.Validator, opts ValidationOptions) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			route := core.CurrentRoute(r.Context())
+			if route == nil {
+				next(w, r)
+				return
+			}
+			op := v.Operation(route.Method, route.Path)
+			if op == nil {
+				next(w, r)
+				return
+			}
+
+			var body []byte
+			if op.RequestBody != nil && r.Body != nil {
+				var err error
+				body, err = io.ReadAll(r.Body)
+				if err != nil {
+					// 读取失败（如请求体超限）时交由处理器按原有逻辑处理
+					r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+					next(w, r)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			if errs := v.ValidateRequest(op, r, body); len(errs) > 0 {
+				writeError(w, r, errors.New(errors.ErrCodeValidation, "request does not match the API specification").
+					WithDetails(errs))
+				return
+			}
+
+			if !opts.ValidateResponses {
+				next(w, r)
+				return
+			}
+
+			// 缓冲响应，校验通过后再写出
+			bw := &bufferedWriter{header: make(http.Header), status: http.StatusOK}
+			next(bw, r)
+
+			if errs := v.ValidateResponse(op, bw.status, bw.body.Bytes()); len(errs) > 0 {
+				if opts.Logger != nil {
+					opts.Logger.Error("Response does not match the API specification",
+						zap.String("method", route.Method),
+						zap.String("route", route.Path),
+						zap.Int("status", bw.status),
+						zap.Any("errors", errs),
+					)
+				}
+				writeError(w, r, errors.New(errors.ErrCodeInternal, "response does not match the API specification").
+					WithDetails(errs))
+				return
+			}
+			bw.flushTo(w)
+		}
+	}
+}
+
+// errReader 总是返回指定错误的读取器
+type errReader struct {
+	err error
+}
+
+// Read 实现 io.Reader 接口
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// bufferedWriter 缓冲整个响应
+type bufferedWriter struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// Header 实现 http.ResponseWriter 接口
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader 实现 http.ResponseWriter 接口
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+}
+
+// Write 实现 http.ResponseWriter 接口
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
+
+// flushTo 将缓冲的响应写出
+func (w *bufferedWriter) flushTo(dst http.ResponseWriter) {
+	for k, vv := range w.header {
+		dst.Header()[k] = vv
+	}
+	dst.WriteHeader(w.status)
+	_, _ = dst.Write(w.body.Bytes())
+}
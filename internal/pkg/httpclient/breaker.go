@@ -0,0 +1,119 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// breakerState 熔断器状态
+type breakerState int
+
+const (
+	stateClosed   breakerState = iota // 正常放行
+	stateOpen                         // 熔断中，直接拒绝请求
+	stateHalfOpen                     // 熔断到期，放行一个探测请求
+)
+
+// String 返回熔断器状态名称
+func (s breakerState) String() string {
+	switch s {
+	case stateOpen:
+		return "open"
+	case stateHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerOptions 熔断器选项
+type BreakerOptions struct {
+	// FailureThreshold 连续失败多少次后熔断，<= 0 表示不启用熔断
+	FailureThreshold int
+	// OpenTimeout 熔断持续时间，到期后放行一个探测请求，成功则恢复
+	OpenTimeout time.Duration
+}
+
+// breaker 单个主机的熔断器
+type breaker struct {
+	opts     BreakerOptions
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool // 半开状态下是否已有探测请求在进行
+}
+
+// allow 判断请求能否发出
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if now.Sub(b.openedAt) < b.opts.OpenTimeout {
+			return false
+		}
+		b.state = stateHalfOpen
+		b.probing = true
+		return true
+	case stateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record 记录请求结果，返回状态是否发生变化以及变化后的状态
+func (b *breaker) record(success bool, now time.Time) (breakerState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	prev := b.state
+	if success {
+		b.state = stateClosed
+		b.failures = 0
+	} else {
+		b.failures++
+		if b.state == stateHalfOpen || b.failures >= b.opts.FailureThreshold {
+			b.state = stateOpen
+			b.openedAt = now
+		}
+	}
+	b.probing = false
+	return b.state, b.state != prev
+}
+
+// release 释放半开状态下的探测名额而不记录结果，用于调用方主动取消的请求
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// breakers 按主机划分的熔断器
+type breakers struct {
+	opts  BreakerOptions
+	mu    sync.Mutex
+	hosts map[string]*breaker
+}
+
+// get 返回主机的熔断器，未启用熔断时返回 nil
+func (bs *breakers) get(host string) *breaker {
+	if bs.opts.FailureThreshold <= 0 {
+		return nil
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	b, ok := bs.hosts[host]
+	if !ok {
+		b = &breaker{opts: bs.opts}
+		bs.hosts[host] = b
+	}
+	return b
+}
@@ -0,0 +1,267 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/logger"
+	"go-api-mono/internal/pkg/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// ErrCircuitOpen 目标主机处于熔断状态，请求未发出
+var ErrCircuitOpen = errors.New("httpclient: circuit breaker is open")
+
+// Options 客户端选项
+type Options struct {
+	Timeout      time.Duration // 单次尝试的超时时间（含读取响应体），整体期限由请求上下文控制
+	MaxRetries   int           // 幂等请求失败后的最大重试次数
+	RetryWaitMin time.Duration // 首次重试前的最大等待时间，之后每次翻倍
+	RetryWaitMax time.Duration // 重试等待时间的上限，同时限制 Retry-After
+	Breaker      BreakerOptions
+	Logger       *logger.Logger    // 为空时不记录日志
+	Transport    http.RoundTripper // 为空时使用 http.DefaultTransport
+}
+
+// DefaultOptions 默认客户端选项
+var DefaultOptions = Options{
+	Timeout:      10 * time.Second,
+	MaxRetries:   2,
+	RetryWaitMin: 100 * time.Millisecond,
+	RetryWaitMax: 2 * time.Second,
+	Breaker: BreakerOptions{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	},
+}
+
+// Client 调用外部服务的 HTTP 客户端，可在多个协程间共享
+// 支持单次请求超时、幂等请求的抖动退避重试与按主机熔断，
+// 并将上下文中的请求ID与链路信息传递给下游服务
+type Client struct {
+	http     *http.Client
+	opts     Options
+	logger   *logger.Logger
+	breakers *breakers
+	now      func() time.Time
+	sleep    func(context.Context, time.Duration) error
+}
+
+// New 创建客户端
+func New(opts Options) *Client {
+	transport := opts.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	log := opts.Logger
+	if log == nil {
+		log = logger.NewNop()
+	}
+
+	return &Client{
+		http:     &http.Client{Timeout: opts.Timeout, Transport: transport},
+		opts:     opts,
+		logger:   log,
+		breakers: &breakers{opts: opts.Breaker, hosts: make(map[string]*breaker)},
+		now:      time.Now,
+		sleep:    sleep,
+	}
+}
+
+// Get 发送 GET 请求，ctx 可以是处理器中的 *core.Context
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post 发送 POST 请求
+// POST 请求仅在携带 Idempotency-Key 请求头时重试，需要重试时请使用 Do
+func (c *Client) Post(ctx context.Context, url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+// Do 发送请求
+// 幂等请求在网络错误或返回 429、502、503、504 时按抖动退避重试，
+// 重试耗尽时返回最后一次的响应或错误；目标主机熔断时返回 ErrCircuitOpen
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.path", req.URL.Path),
+		))
+	defer span.End()
+
+	req = req.Clone(ctx)
+	if req.Header.Get(core.RequestIDHeader) == "" {
+		if requestID := core.RequestID(ctx); requestID != "" {
+			req.Header.Set(core.RequestIDHeader, requestID)
+		}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	log := c.logger.WithContext(ctx).With(
+		zap.String("method", req.Method),
+		zap.String("host", req.URL.Host),
+		zap.String("path", req.URL.Path),
+		zap.String("request_id", req.Header.Get(core.RequestIDHeader)),
+	)
+
+	resp, err := c.do(ctx, req, log)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// do 按重试策略发送请求
+func (c *Client) do(ctx context.Context, req *http.Request, log *logger.Logger) (*http.Response, error) {
+	retryable := isIdempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+	b := c.breakers.get(req.URL.Host)
+
+	for attempt := 0; ; attempt++ {
+		if b != nil && !b.allow(c.now()) {
+			log.Warn("Outbound request rejected by circuit breaker", zap.Int("attempt", attempt+1))
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, req.URL.Host)
+		}
+
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				if b != nil {
+					b.release()
+				}
+				return nil, fmt.Errorf("httpclient: failed to rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		start := c.now()
+		resp, err := c.http.Do(req)
+		fields := []zap.Field{zap.Int("attempt", attempt+1), zap.Duration("duration", c.now().Sub(start))}
+		if resp != nil {
+			fields = append(fields, zap.Int("status", resp.StatusCode))
+		}
+
+		// 调用方取消或超出整体期限时不计入熔断，也不再重试
+		if err != nil && ctx.Err() != nil {
+			if b != nil {
+				b.release()
+			}
+			log.Warn("Outbound request canceled", append(fields, zap.Error(err))...)
+			return nil, err
+		}
+
+		if b != nil {
+			success := err == nil && resp.StatusCode < http.StatusInternalServerError
+			if state, changed := b.record(success, c.now()); changed {
+				log.Warn("Circuit breaker state changed", zap.String("state", state.String()))
+			}
+		}
+
+		if !retryable || attempt >= c.opts.MaxRetries || !shouldRetry(resp, err) {
+			if err != nil {
+				log.Error("Outbound request failed", append(fields, zap.Error(err))...)
+				return nil, err
+			}
+			log.Debug("Outbound request completed", fields...)
+			return resp, nil
+		}
+
+		wait := c.backoff(attempt, resp)
+		log.Warn("Outbound request failed, retrying", append(fields, zap.Duration("wait", wait), zap.Error(err))...)
+		if resp != nil {
+			// 读取并关闭响应体，使连接可以复用
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+			resp.Body.Close()
+		}
+		if err := c.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isIdempotent 判断请求是否可以安全重试
+// POST、PATCH 携带 Idempotency-Key 时服务端会去重，同样视为幂等
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get("Idempotency-Key") != ""
+	}
+}
+
+// shouldRetry 判断失败的请求是否值得重试
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff 返回第 attempt 次失败后的等待时间
+// 使用全抖动的指数退避，响应携带 Retry-After 时以其为准，均不超过 RetryWaitMax
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, c.opts.RetryWaitMax)
+		}
+	}
+
+	ceiling := c.opts.RetryWaitMin << attempt
+	if ceiling <= 0 || ceiling > c.opts.RetryWaitMax {
+		ceiling = c.opts.RetryWaitMax
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+// sleep 等待 d 或直到上下文结束
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,196 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-mono/internal/pkg/core"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestClient 创建不实际等待的客户端，返回的切片记录每次重试的等待时间
+func newTestClient(opts Options) (*Client, *[]time.Duration) {
+	c := New(opts)
+	var waits []time.Duration
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return c, &waits
+}
+
+// flakyServer 前 failures 次请求返回 status，之后返回 200
+func flakyServer(t *testing.T, failures int32, status int) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if calls.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestClientRetry(t *testing.T) {
+	opts := DefaultOptions
+	opts.Breaker.FailureThreshold = 0
+
+	tests := []struct {
+		name      string
+		method    string
+		header    map[string]string
+		status    int
+		failures  int32
+		wantCalls int32
+		wantCode  int
+	}{
+		{"GET 在 503 后重试成功", http.MethodGet, nil, http.StatusServiceUnavailable, 2, 3, http.StatusOK},
+		{"重试耗尽返回最后的响应", http.MethodGet, nil, http.StatusBadGateway, 5, 3, http.StatusBadGateway},
+		{"客户端错误不重试", http.MethodGet, nil, http.StatusNotFound, 1, 1, http.StatusNotFound},
+		{"内部错误不重试", http.MethodPut, nil, http.StatusInternalServerError, 1, 1, http.StatusInternalServerError},
+		{"POST 不重试", http.MethodPost, nil, http.StatusServiceUnavailable, 1, 1, http.StatusServiceUnavailable},
+		{"携带幂等键的 POST 重试", http.MethodPost, map[string]string{"Idempotency-Key": "k1"}, http.StatusServiceUnavailable, 1, 2, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := flakyServer(t, tt.failures, tt.status)
+			c, waits := newTestClient(opts)
+
+			req, err := http.NewRequest(tt.method, srv.URL+"/hooks", strings.NewReader("payload"))
+			require.NoError(t, err)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			resp, err := c.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+			assert.Len(t, *waits, int(tt.wantCalls-1))
+			if resp.StatusCode == http.StatusOK {
+				// 重试时重新发送完整的请求体
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, "payload", string(body))
+			}
+		})
+	}
+}
+
+func TestClientBackoff(t *testing.T) {
+	c := New(Options{RetryWaitMin: 100 * time.Millisecond, RetryWaitMax: time.Second})
+
+	for attempt := 0; attempt < 6; attempt++ {
+		ceiling := min(100*time.Millisecond<<attempt, time.Second)
+		for i := 0; i < 20; i++ {
+			wait := c.backoff(attempt, nil)
+			assert.GreaterOrEqual(t, wait, time.Duration(0))
+			assert.LessOrEqual(t, wait, ceiling)
+		}
+	}
+
+	// Retry-After 优先，但不超过上限
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"0"}}}
+	assert.Equal(t, time.Duration(0), c.backoff(0, resp))
+	resp.Header.Set("Retry-After", "120")
+	assert.Equal(t, time.Second, c.backoff(0, resp))
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	srv, calls := flakyServer(t, 3, http.StatusInternalServerError)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, _ := newTestClient(Options{Breaker: BreakerOptions{FailureThreshold: 3, OpenTimeout: time.Minute}})
+	c.now = func() time.Time { return now }
+
+	get := func() (*http.Response, error) {
+		resp, err := c.Get(context.Background(), srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	// 连续失败达到阈值后熔断，请求不再发出
+	for i := 0; i < 3; i++ {
+		_, err := get()
+		require.NoError(t, err)
+	}
+	_, err := get()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// 熔断到期后放行探测请求，成功则恢复
+	now = now.Add(time.Minute)
+	resp, err := get()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	_, err = get()
+	assert.NoError(t, err)
+
+	// 熔断按主机划分
+	other, _ := flakyServer(t, 0, http.StatusOK)
+	resp, err = c.Get(context.Background(), other.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestClientPropagation(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	ctx = core.WithRequestID(ctx, "req-123")
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	c := core.NewContext(r, httptest.NewRecorder(), nil)
+
+	resp, err := New(DefaultOptions).Get(c, srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "req-123", header.Get(core.RequestIDHeader))
+	assert.Contains(t, header.Get("traceparent"), traceID.String())
+}
+
+func TestClientCanceled(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c := New(Options{MaxRetries: 5, RetryWaitMax: time.Hour})
+
+	// 等待重试期间上下文结束时立即返回
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.Get(ctx, srv.URL)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), calls.Load())
+}
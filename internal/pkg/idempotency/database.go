@@ -0,0 +1,149 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go-api-mono/internal/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// recordModel 幂等记录的数据库模型
+type recordModel struct {
+	Key         string    `gorm:"primaryKey;size:64"`
+	Fingerprint string    `gorm:"size:64"`
+	Completed   bool      `gorm:"not null"`
+	StatusCode  int       `gorm:"not null"`
+	Header      []byte    `gorm:"type:blob"`
+	Body        []byte    `gorm:"type:mediumblob"`
+	ExpiresAt   time.Time `gorm:"index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TableName 指定表名
+func (recordModel) TableName() string {
+	return "idempotency_keys"
+}
+
+// DBStore 基于数据库的幂等记录存储，适用于多实例部署
+type DBStore struct {
+	db  *database.DB
+	now func() time.Time
+}
+
+// NewDBStore 创建数据库存储
+func NewDBStore(db *database.DB) *DBStore {
+	return &DBStore{db: db, now: time.Now}
+}
+
+// acquireAttempts 占用幂等键的最大尝试次数
+// 已有记录在插入与读取之间被释放或过期清理时重新尝试占用
+const acquireAttempts = 3
+
+// Acquire 实现 Store 接口
+func (s *DBStore) Acquire(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	for attempt := 1; ; attempt++ {
+		record, acquired, err := s.acquire(ctx, key, fingerprint, ttl)
+		if err != ErrNotFound || attempt == acquireAttempts {
+			return record, acquired, err
+		}
+	}
+}
+
+// acquire 尝试占用一次幂等键，已有记录在插入与读取之间消失时返回 ErrNotFound
+func (s *DBStore) acquire(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	now := s.now()
+
+	// 清理该键已过期的记录，使其可以被重新占用
+	if err := s.db.WithContext(ctx).
+		Where("`key` = ? AND expires_at <= ?", key, now).
+		Delete(&recordModel{}).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to purge expired idempotency key: %w", err)
+	}
+
+	// 依赖主键唯一性实现原子占用
+	model := &recordModel{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(ttl),
+	}
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(model)
+	if result.Error != nil {
+		return nil, false, fmt.Errorf("failed to acquire idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return nil, true, nil
+	}
+
+	var existing recordModel
+	if err := s.db.WithContext(ctx).Where("`key` = ?", key).First(&existing).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, false, ErrNotFound
+		}
+		return nil, false, fmt.Errorf("failed to load idempotency key: %w", err)
+	}
+
+	record, err := existing.toRecord()
+	if err != nil {
+		return nil, false, err
+	}
+	return record, false, nil
+}
+
+// Complete 实现 Store 接口
+func (s *DBStore) Complete(ctx context.Context, record *Record) error {
+	header, err := json.Marshal(record.Header)
+	if err != nil {
+		return fmt.Errorf("failed to encode response header: %w", err)
+	}
+
+	result := s.db.WithContext(ctx).Model(&recordModel{}).
+		Where("`key` = ?", record.Key).
+		Updates(map[string]interface{}{
+			"completed":   true,
+			"status_code": record.StatusCode,
+			"header":      header,
+			"body":        record.Body,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Release 实现 Store 接口
+func (s *DBStore) Release(ctx context.Context, key string) error {
+	if err := s.db.WithContext(ctx).Where("`key` = ?", key).Delete(&recordModel{}).Error; err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// toRecord 转换为领域记录
+func (m *recordModel) toRecord() (*Record, error) {
+	record := &Record{
+		Key:         m.Key,
+		Fingerprint: m.Fingerprint,
+		Completed:   m.Completed,
+		StatusCode:  m.StatusCode,
+		Body:        m.Body,
+		ExpiresAt:   m.ExpiresAt,
+	}
+	if len(m.Header) > 0 {
+		var header http.Header
+		if err := json.Unmarshal(m.Header, &header); err != nil {
+			return nil, fmt.Errorf("failed to decode response header: %w", err)
+		}
+		record.Header = header
+	}
+	return record, nil
+}
@@ -0,0 +1,94 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore 基于内存的幂等记录存储，仅适用于单实例部署
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*Record
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// sweepInterval 清理过期记录的最小间隔
+const sweepInterval = time.Minute
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]*Record),
+		now:     time.Now,
+	}
+}
+
+// Acquire 实现 Store 接口
+func (s *MemoryStore) Acquire(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweepLocked(now)
+
+	if record, ok := s.records[key]; ok && !record.Expired(now) {
+		return copyRecord(record), false, nil
+	}
+
+	s.records[key] = &Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(ttl),
+	}
+	return nil, true, nil
+}
+
+// Complete 实现 Store 接口
+func (s *MemoryStore) Complete(ctx context.Context, record *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.records[record.Key]
+	if !ok {
+		return ErrNotFound
+	}
+	completed := copyRecord(record)
+	completed.Completed = true
+	completed.ExpiresAt = existing.ExpiresAt
+	s.records[record.Key] = completed
+	return nil
+}
+
+// Release 实现 Store 接口
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// sweepLocked 定期清理过期记录，调用方需持有锁
+func (s *MemoryStore) sweepLocked(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, record := range s.records {
+		if record.Expired(now) {
+			delete(s.records, key)
+		}
+	}
+}
+
+// copyRecord 复制记录，避免调用方修改存储中的数据
+func copyRecord(r *Record) *Record {
+	c := *r
+	c.Header = r.Header.Clone()
+	if r.Body != nil {
+		c.Body = append([]byte(nil), r.Body...)
+	}
+	return &c
+}
@@ -0,0 +1,41 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrNotFound 表示幂等记录不存在
+	ErrNotFound = errors.New("idempotency record not found")
+)
+
+// Record 幂等记录
+type Record struct {
+	Key         string      // 作用域内唯一的幂等键
+	Fingerprint string      // 请求指纹（方法、路径与请求体的摘要）
+	Completed   bool        // 首个请求是否已完成
+	StatusCode  int         // 捕获的响应状态码
+	Header      http.Header // 捕获的响应头
+	Body        []byte      // 捕获的响应体
+	ExpiresAt   time.Time   // 过期时间
+}
+
+// Expired 判断记录是否已过期
+func (r *Record) Expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && !now.Before(r.ExpiresAt)
+}
+
+// Store 幂等记录存储接口
+type Store interface {
+	// Acquire 尝试占用幂等键
+	// 键不存在（或已过期）时写入一条进行中的记录并返回 (nil, true)；
+	// 否则返回已有记录和 false
+	Acquire(ctx context.Context, key, fingerprint string, ttl time.Duration) (*Record, bool, error)
+	// Complete 保存首个请求的响应
+	Complete(ctx context.Context, record *Record) error
+	// Release 释放幂等键，使后续请求可以重新执行
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,144 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-api-mono/internal/pkg/database"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testClock 可手动推进的时钟
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+// newTestDB 创建临时的 sqlite 数据库并建表
+func newTestDB(t *testing.T) *database.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "idempotency.db")), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&recordModel{}))
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return &database.DB{DB: db}
+}
+
+func TestStore(t *testing.T) {
+	stores := map[string]func(t *testing.T, clock *testClock) Store{
+		"memory": func(t *testing.T, clock *testClock) Store {
+			s := NewMemoryStore()
+			s.now = clock.Now
+			return s
+		},
+		"database": func(t *testing.T, clock *testClock) Store {
+			s := NewDBStore(newTestDB(t))
+			s.now = clock.Now
+			return s
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+			store := newStore(t, clock)
+
+			t.Run("占用新的键", func(t *testing.T) {
+				record, acquired, err := store.Acquire(ctx, "k1", "fp-1", time.Hour)
+				require.NoError(t, err)
+				assert.True(t, acquired)
+				assert.Nil(t, record)
+			})
+
+			t.Run("进行中的键返回已有记录", func(t *testing.T) {
+				record, acquired, err := store.Acquire(ctx, "k1", "fp-2", time.Hour)
+				require.NoError(t, err)
+				assert.False(t, acquired)
+				require.NotNil(t, record)
+				assert.Equal(t, "fp-1", record.Fingerprint)
+				assert.False(t, record.Completed)
+			})
+
+			t.Run("完成后返回保存的响应", func(t *testing.T) {
+				require.NoError(t, store.Complete(ctx, &Record{
+					Key:         "k1",
+					Fingerprint: "fp-1",
+					StatusCode:  http.StatusCreated,
+					Header:      http.Header{"Content-Type": {"application/json"}},
+					Body:        []byte(`{"id":1}`),
+				}))
+
+				record, acquired, err := store.Acquire(ctx, "k1", "fp-1", time.Hour)
+				require.NoError(t, err)
+				assert.False(t, acquired)
+				require.NotNil(t, record)
+				assert.True(t, record.Completed)
+				assert.Equal(t, http.StatusCreated, record.StatusCode)
+				assert.Equal(t, "application/json", record.Header.Get("Content-Type"))
+				assert.Equal(t, `{"id":1}`, string(record.Body))
+				assert.True(t, record.ExpiresAt.Equal(clock.now.Add(time.Hour)), record.ExpiresAt)
+			})
+
+			t.Run("过期后可以重新占用", func(t *testing.T) {
+				clock.now = clock.now.Add(time.Hour)
+				record, acquired, err := store.Acquire(ctx, "k1", "fp-3", time.Hour)
+				require.NoError(t, err)
+				assert.True(t, acquired)
+				assert.Nil(t, record)
+			})
+
+			t.Run("释放后可以重新占用", func(t *testing.T) {
+				require.NoError(t, store.Release(ctx, "k1"))
+				_, acquired, err := store.Acquire(ctx, "k1", "fp-4", time.Hour)
+				require.NoError(t, err)
+				assert.True(t, acquired)
+			})
+
+			t.Run("完成不存在的键", func(t *testing.T) {
+				assert.ErrorIs(t, store.Complete(ctx, &Record{Key: "missing"}), ErrNotFound)
+			})
+		})
+	}
+}
+
+func TestDBStoreAcquireReleasedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	store := NewDBStore(db)
+
+	_, acquired, err := store.Acquire(ctx, "k1", "fp-1", time.Hour)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// 模拟其他请求在插入与读取之间释放该键
+	released := false
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:release", func(tx *gorm.DB) {
+		if !released {
+			released = true
+			require.NoError(t, store.Release(ctx, "k1"))
+		}
+	}))
+
+	record, acquired, err := store.Acquire(ctx, "k1", "fp-2", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, released)
+	assert.True(t, acquired)
+	assert.Nil(t, record)
+}
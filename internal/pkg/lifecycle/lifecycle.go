@@ -0,0 +1,120 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-api-mono/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Hook 组件的生命周期钩子
+type Hook struct {
+	Name string
+	// Order 启动顺序，越小越先启动、越后停止；相同顺序的钩子按添加顺序启动、逆序停止
+	Order   int
+	OnStart func(ctx context.Context) error // 可以为 nil
+	OnStop  func(ctx context.Context) error // 可以为 nil
+}
+
+// Manager 按顺序启动与停止组件，并记录每一步的耗时
+type Manager struct {
+	logger  *logger.Logger
+	now     func() time.Time
+	mu      sync.Mutex
+	hooks   []Hook
+	started int  // 已启动的钩子数量，启动失败时只停止这些钩子
+	stopped bool // 是否已经停止，重复停止时直接返回
+}
+
+// New 创建生命周期管理器
+func New(log *logger.Logger) *Manager {
+	return &Manager{logger: log, now: time.Now}
+}
+
+// Append 添加钩子，必须在 Start 之前调用
+func (m *Manager) Append(hooks ...Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hooks...)
+	sort.SliceStable(m.hooks, func(i, j int) bool { return m.hooks[i].Order < m.hooks[j].Order })
+}
+
+// Start 按顺序执行 OnStart，某个钩子失败时逆序停止已启动的钩子并返回错误
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	begin := m.now()
+	for i, h := range m.hooks {
+		if h.OnStart != nil {
+			if err := m.run(ctx, "start", h.Name, h.OnStart); err != nil {
+				m.started = i
+				m.logger.Error("Startup failed, rolling back", zap.String("hook", h.Name), zap.Error(err))
+				if stopErr := m.stop(ctx); stopErr != nil {
+					err = errors.Join(err, stopErr)
+				}
+				return fmt.Errorf("failed to start %s: %w", h.Name, err)
+			}
+		}
+		m.started = i + 1
+	}
+	m.logger.Info("Startup complete", zap.Duration("duration", m.now().Sub(begin)))
+	return nil
+}
+
+// Stop 逆序执行已启动钩子的 OnStop，某个钩子失败时继续停止其余钩子，返回所有错误
+// 未调用 Start 时停止全部钩子，以便释放创建时已打开的资源；重复调用直接返回nil
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopped {
+		return nil
+	}
+	if m.started == 0 {
+		m.started = len(m.hooks)
+	}
+	begin := m.now()
+	err := m.stop(ctx)
+	m.logger.Info("Shutdown complete", zap.Duration("duration", m.now().Sub(begin)), zap.Bool("clean", err == nil))
+	return err
+}
+
+// stop 逆序停止已启动的钩子
+func (m *Manager) stop(ctx context.Context) error {
+	m.stopped = true
+	var errs []error
+	for i := m.started - 1; i >= 0; i-- {
+		h := m.hooks[i]
+		if h.OnStop == nil {
+			continue
+		}
+		if err := m.run(ctx, "stop", h.Name, h.OnStop); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", h.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run 执行单个钩子并记录耗时
+func (m *Manager) run(ctx context.Context, phase, name string, fn func(context.Context) error) error {
+	begin := m.now()
+	err := fn(ctx)
+	fields := []zap.Field{
+		zap.String("phase", phase),
+		zap.String("hook", name),
+		zap.Duration("duration", m.now().Sub(begin)),
+	}
+	if err != nil {
+		m.logger.Error("Lifecycle hook failed", append(fields, zap.Error(err))...)
+		return err
+	}
+	m.logger.Info("Lifecycle hook finished", fields...)
+	return nil
+}
@@ -0,0 +1,109 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-api-mono/internal/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder 记录钩子的执行顺序
+type recorder struct {
+	calls []string
+}
+
+// hook 创建记录调用的钩子，fail 指定失败的阶段
+func (r *recorder) hook(name string, order int, fail string) Hook {
+	step := func(phase string) func(context.Context) error {
+		return func(context.Context) error {
+			r.calls = append(r.calls, phase+" "+name)
+			if phase == fail {
+				return errors.New(name + " failed")
+			}
+			return nil
+		}
+	}
+	return Hook{Name: name, Order: order, OnStart: step("start"), OnStop: step("stop")}
+}
+
+func TestManagerOrder(t *testing.T) {
+	r := &recorder{}
+	m := New(logger.NewNop())
+	m.Append(r.hook("server", 20, ""), r.hook("database", 0, ""))
+	m.Append(r.hook("cache", 0, ""), Hook{Name: "readiness", Order: 30})
+
+	require.NoError(t, m.Start(context.Background()))
+	require.NoError(t, m.Stop(context.Background()))
+	// 重复停止不会再次执行钩子
+	require.NoError(t, m.Stop(context.Background()))
+
+	assert.Equal(t, []string{
+		"start database", "start cache", "start server",
+		"stop server", "stop cache", "stop database",
+	}, r.calls)
+}
+
+func TestManagerErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    bool
+		hooks    func(r *recorder) []Hook
+		wantErr  string
+		expected []string
+	}{
+		{
+			name:  "start failure rolls back started hooks",
+			start: true,
+			hooks: func(r *recorder) []Hook {
+				return []Hook{r.hook("database", 0, ""), r.hook("server", 10, "start"), r.hook("readiness", 20, "")}
+			},
+			wantErr:  "failed to start server: server failed",
+			expected: []string{"start database", "start server", "stop database"},
+		},
+		{
+			name:  "stop failure continues with remaining hooks",
+			start: true,
+			hooks: func(r *recorder) []Hook {
+				return []Hook{r.hook("database", 0, "stop"), r.hook("server", 10, "stop"), r.hook("readiness", 20, "")}
+			},
+			wantErr: "failed to stop server: server failed\nfailed to stop database: database failed",
+			expected: []string{
+				"start database", "start server", "start readiness",
+				"stop readiness", "stop server", "stop database",
+			},
+		},
+		{
+			name: "stop without start releases all hooks",
+			hooks: func(r *recorder) []Hook {
+				return []Hook{r.hook("database", 0, ""), r.hook("server", 10, "")}
+			},
+			expected: []string{"stop server", "stop database"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			m := New(logger.NewNop())
+			m.Append(tt.hooks(r)...)
+
+			var err error
+			if tt.start {
+				err = m.Start(context.Background())
+			}
+			if err == nil {
+				err = m.Stop(context.Background())
+			}
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.expected, r.calls)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrShuttingDown 关闭开始后登记新任务时返回的错误
+var ErrShuttingDown = errors.New("shutting down")
+
+// Tasks 跟踪后台任务与 WebSocket 等长连接，关闭时等待它们结束
+// 关闭开始时取消 Context 返回的上下文，任务应在其取消后尽快收尾
+type Tasks struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	running map[string]int
+	total   int
+	closed  bool
+	idle    chan struct{} // 等待中且仍有任务时非nil，最后一个任务结束时关闭
+}
+
+// NewTasks 创建任务跟踪器
+func NewTasks() *Tasks {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Tasks{ctx: ctx, cancel: cancel, running: make(map[string]int)}
+}
+
+// Context 返回关闭开始时取消的上下文
+func (t *Tasks) Context() context.Context {
+	return t.ctx
+}
+
+// Track 登记一个正在运行的任务，返回任务结束时调用的函数，重复调用无副作用
+// 关闭开始后返回 ErrShuttingDown，调用方应拒绝新的任务或连接
+func (t *Tasks) Track(name string) (func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, ErrShuttingDown
+	}
+	t.running[name]++
+	t.total++
+
+	var once sync.Once
+	return func() { once.Do(func() { t.done(name) }) }, nil
+}
+
+// Go 在新的协程中运行任务，任务收到的上下文在关闭开始时取消
+func (t *Tasks) Go(name string, fn func(ctx context.Context)) error {
+	done, err := t.Track(name)
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer done()
+		fn(t.ctx)
+	}()
+	return nil
+}
+
+// done 标记任务结束
+func (t *Tasks) done(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.running[name]--; t.running[name] == 0 {
+		delete(t.running, name)
+	}
+	if t.total--; t.total == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// Running 返回正在运行的任务数量
+func (t *Tasks) Running() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+// Wait 拒绝新的任务，取消任务上下文并等待所有任务结束
+// ctx 结束时仍未完成的任务会列在返回的错误中
+func (t *Tasks) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	t.cancel()
+	if t.total == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tasks still running (%s): %w", t.summary(), ctx.Err())
+	}
+}
+
+// summary 返回正在运行的任务摘要，如 "export=1, websocket=3"
+func (t *Tasks) summary() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	names := make([]string, 0, len(t.running))
+	for name, n := range t.running {
+		names = append(names, fmt.Sprintf("%s=%d", name, n))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
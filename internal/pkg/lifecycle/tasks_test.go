@@ -0,0 +1,51 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTasksWait(t *testing.T) {
+	tasks := NewTasks()
+
+	// 后台任务在上下文取消后结束
+	require.NoError(t, tasks.Go("job", func(ctx context.Context) {
+		<-ctx.Done()
+	}))
+	// 长连接由调用方自行标记结束
+	done, err := tasks.Track("websocket")
+	require.NoError(t, err)
+	assert.Equal(t, 2, tasks.Running())
+
+	go func() {
+		<-tasks.Context().Done()
+		done()
+		done()
+	}()
+	require.NoError(t, tasks.Wait(context.Background()))
+	assert.Equal(t, 0, tasks.Running())
+
+	// 关闭开始后拒绝新的任务
+	_, err = tasks.Track("websocket")
+	assert.ErrorIs(t, err, ErrShuttingDown)
+	assert.ErrorIs(t, tasks.Go("job", func(context.Context) {}), ErrShuttingDown)
+}
+
+func TestTasksWaitTimeout(t *testing.T) {
+	tasks := NewTasks()
+	done, err := tasks.Track("websocket")
+	require.NoError(t, err)
+	defer done()
+	_, err = tasks.Track("export")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = tasks.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "export=1, websocket=1")
+}
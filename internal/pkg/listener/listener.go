@@ -0,0 +1,91 @@
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// listenFDsStart 继承的第一个文件描述符，与 systemd 的 SD_LISTEN_FDS_START 一致
+const listenFDsStart = 3
+
+// 传递监听器的环境变量，遵循 systemd socket activation 的约定
+const (
+	envListenFDs     = "LISTEN_FDS"     // 继承的文件描述符数量
+	envListenPID     = "LISTEN_PID"     // 文件描述符的接收进程，升级时不设置
+	envListenFDNames = "LISTEN_FDNAMES" // 以 ":" 分隔的文件描述符名称
+)
+
+// Inherit 返回从 systemd socket activation 或升级前的进程继承的监听器，按名称索引
+// 文件描述符未命名（或名称为 "unknown"）时按顺序使用 names 中的名称；没有继承的监听器时返回nil
+// 读取后清除相关环境变量，避免再传递给子进程
+func Inherit(names ...string) (map[string]net.Listener, error) {
+	return inheritFrom(listenFDsStart, names)
+}
+
+// inheritFrom 从文件描述符 start 开始读取继承的监听器
+func inheritFrom(start int, names []string) (map[string]net.Listener, error) {
+	count := os.Getenv(envListenFDs)
+	pid := os.Getenv(envListenPID)
+	fdNames := strings.Split(os.Getenv(envListenFDNames), ":")
+	for _, env := range []string{envListenFDs, envListenPID, envListenFDNames} {
+		os.Unsetenv(env)
+	}
+
+	if count == "" {
+		return nil, nil
+	}
+	// 指定了接收进程时只由该进程使用
+	if pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid %s %q", envListenFDs, count)
+	}
+
+	listeners := make(map[string]net.Listener, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("fd%d", start+i)
+		switch {
+		case i < len(fdNames) && fdNames[i] != "" && fdNames[i] != "unknown":
+			name = fdNames[i]
+		case i < len(names):
+			name = names[i]
+		}
+
+		f := os.NewFile(uintptr(start+i), name)
+		ln, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			closeAll(listeners)
+			return nil, fmt.Errorf("inherited listener %s: %w", name, err)
+		}
+		if _, ok := listeners[name]; ok {
+			ln.Close()
+			closeAll(listeners)
+			return nil, fmt.Errorf("duplicate inherited listener %s", name)
+		}
+		listeners[name] = ln
+	}
+	return listeners, nil
+}
+
+// closeAll 关闭所有监听器
+func closeAll(listeners map[string]net.Listener) {
+	for _, ln := range listeners {
+		ln.Close()
+	}
+}
+
+// file 返回监听器底层文件描述符的副本
+func file(ln net.Listener) (*os.File, error) {
+	filer, ok := ln.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, errors.New("listener does not expose a file descriptor")
+	}
+	return filer.File()
+}
@@ -0,0 +1,111 @@
+//go:build unix
+
+package listener
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listenerFDs 创建 n 个监听器，将其文件描述符复制到连续的编号上，返回起始编号与原监听器
+// 复制的文件描述符由 inheritFrom 接管，未被接管时由调用方关闭
+func listenerFDs(t *testing.T, n int) (int, []net.Listener) {
+	t.Helper()
+	var listeners []net.Listener
+	for i := 0; i < n; i++ {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		t.Cleanup(func() { ln.Close() })
+		listeners = append(listeners, ln)
+	}
+	var fds []int
+	for _, ln := range listeners {
+		raw, err := ln.(*net.TCPListener).SyscallConn()
+		require.NoError(t, err)
+		var fd int
+		require.NoError(t, raw.Control(func(s uintptr) { fd, err = syscall.Dup(int(s)) }))
+		require.NoError(t, err)
+		fds = append(fds, fd)
+	}
+	// 新复制的文件描述符编号通常连续，不连续时跳过测试
+	for i := 1; i < n; i++ {
+		if fds[i] != fds[0]+i {
+			closeFDs(fds[0], n)
+			t.Skip("file descriptors are not contiguous")
+		}
+	}
+	return fds[0], listeners
+}
+
+// closeFDs 关闭从 start 开始的 n 个文件描述符
+func closeFDs(start, n int) {
+	for i := 0; i < n; i++ {
+		syscall.Close(start + i)
+	}
+}
+
+func TestInherit(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name:      "named by LISTEN_FDNAMES",
+			env:       map[string]string{envListenFDs: "2", envListenFDNames: "admin:api"},
+			wantNames: []string{"admin", "api"},
+		},
+		{
+			name:      "unnamed systemd sockets use default names",
+			env:       map[string]string{envListenFDs: "2", envListenPID: strconv.Itoa(os.Getpid())},
+			wantNames: []string{"api", "admin"},
+		},
+		{
+			name: "sockets for another process",
+			env:  map[string]string{envListenFDs: "2", envListenPID: "1"},
+		},
+		{
+			name: "no sockets",
+			env:  map[string]string{},
+		},
+		{
+			name:    "invalid count",
+			env:     map[string]string{envListenFDs: "two"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, originals := listenerFDs(t, 2)
+			for _, env := range []string{envListenFDs, envListenPID, envListenFDNames} {
+				t.Setenv(env, tt.env[env])
+			}
+
+			listeners, err := inheritFrom(start, []string{"api", "admin"})
+			if listeners == nil {
+				closeFDs(start, 2)
+			}
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			// 读取后清除环境变量
+			assert.Empty(t, os.Getenv(envListenFDs))
+			require.Len(t, listeners, len(tt.wantNames))
+			for i, name := range tt.wantNames {
+				require.Contains(t, listeners, name)
+				assert.Equal(t, originals[i].Addr().String(), listeners[name].Addr().String())
+				listeners[name].Close()
+			}
+		})
+	}
+}
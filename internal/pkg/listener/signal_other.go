@@ -0,0 +1,8 @@
+//go:build !unix
+
+package listener
+
+import "os"
+
+// UpgradeSignal 触发升级的信号，当前平台不支持升级时为nil
+var UpgradeSignal os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package listener
+
+import (
+	"os"
+	"syscall"
+)
+
+// UpgradeSignal 触发升级的信号
+var UpgradeSignal os.Signal = syscall.SIGUSR2
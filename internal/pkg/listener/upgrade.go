@@ -0,0 +1,163 @@
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envReadyFD 升级时新进程通知就绪的管道文件描述符
+const envReadyFD = "UPGRADE_READY_FD"
+
+// readyMessage 新进程就绪时写入管道的内容
+const readyMessage = "ready\n"
+
+// UpgradeOptions 升级选项
+type UpgradeOptions struct {
+	Listeners    map[string]net.Listener // 传递给新进程的监听器，按名称排序后依次传递
+	ReadyTimeout time.Duration           // 等待新进程就绪的时长上限
+	Path         string                  // 新进程的可执行文件，为空时使用当前进程的启动路径
+	Args         []string                // 新进程的参数，为nil时使用当前进程的参数
+}
+
+// DefaultUpgradeOptions 默认升级选项
+var DefaultUpgradeOptions = UpgradeOptions{
+	ReadyTimeout: 30 * time.Second,
+}
+
+// Upgrade 启动新的可执行文件并传递监听器，等待新进程调用 Ready 后返回新进程
+// 传递期间两个进程共享同一个监听套接字，新连接不会被拒绝；调用方随后应停止接受连接并退出。
+// 新进程在超时前退出或未就绪时终止新进程并返回错误，当前进程继续提供服务
+func Upgrade(opts UpgradeOptions) (*os.Process, error) {
+	path := opts.Path
+	if path == "" {
+		// 使用启动路径而不是 os.Executable：部署替换可执行文件后后者指向已删除的旧文件
+		p, err := exec.LookPath(os.Args[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate executable: %w", err)
+		}
+		path = p
+	}
+	args := opts.Args
+	if args == nil {
+		args = os.Args[1:]
+	}
+
+	names := make([]string, 0, len(opts.Listeners))
+	for name := range opts.Listeners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := make([]*os.File, 0, len(names)+1)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	for _, name := range names {
+		f, err := file(opts.Listeners[name])
+		if err != nil {
+			return nil, fmt.Errorf("listener %s: %w", name, err)
+		}
+		files = append(files, f)
+	}
+
+	ready, readyW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ready pipe: %w", err)
+	}
+	defer ready.Close()
+	files = append(files, readyW)
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(childEnv(),
+		envListenFDs+"="+strconv.Itoa(len(names)),
+		envListenFDNames+"="+strings.Join(names, ":"),
+		envReadyFD+"="+strconv.Itoa(listenFDsStart+len(names)),
+	)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start new process: %w", err)
+	}
+	// 关闭当前进程持有的写端，新进程退出时读端才能读到 EOF
+	readyW.Close()
+	files = files[:len(files)-1]
+
+	if err := waitReady(ready, opts.ReadyTimeout); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+	return cmd.Process, nil
+}
+
+// waitReady 等待新进程写入就绪消息
+func waitReady(ready *os.File, timeout time.Duration) error {
+	if err := ready.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return fmt.Errorf("failed to set ready timeout: %w", err)
+	}
+	buf := make([]byte, len(readyMessage))
+	_, err := io.ReadFull(ready, buf)
+	switch {
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		return fmt.Errorf("new process not ready after %s", timeout)
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return errors.New("new process exited before becoming ready")
+	case err != nil:
+		return fmt.Errorf("failed to wait for new process: %w", err)
+	case string(buf) != readyMessage:
+		return fmt.Errorf("unexpected ready message %q", buf)
+	}
+	return nil
+}
+
+// childEnv 返回新进程的环境变量，去掉当前进程继承的监听器相关变量
+func childEnv() []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		switch name {
+		case envListenFDs, envListenPID, envListenFDNames, envReadyFD:
+			continue
+		}
+		env = append(env, kv)
+	}
+	return env
+}
+
+// Upgrading 判断当前进程是否由 Upgrade 启动且尚未通知就绪
+func Upgrading() bool {
+	return os.Getenv(envReadyFD) != ""
+}
+
+// Ready 通知启动当前进程的旧进程已经就绪，旧进程随后停止接受连接并退出
+// 当前进程不是由 Upgrade 启动时直接返回nil
+func Ready() error {
+	value := os.Getenv(envReadyFD)
+	if value == "" {
+		return nil
+	}
+	os.Unsetenv(envReadyFD)
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", envReadyFD, value)
+	}
+	f := os.NewFile(uintptr(fd), "upgrade-ready")
+	defer f.Close()
+	if _, err := f.WriteString(readyMessage); err != nil {
+		return fmt.Errorf("failed to notify parent process: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package listener
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// helperEnv 作为升级后的新进程运行测试二进制时设置的环境变量，取值为新进程的行为
+const helperEnv = "LISTENER_TEST_HELPER"
+
+// TestHelperProcess 模拟升级后的新进程：继承监听器，通知就绪后应答一个连接
+func TestHelperProcess(t *testing.T) {
+	mode := os.Getenv(helperEnv)
+	if mode == "" {
+		t.Skip("helper process only")
+	}
+	if mode == "fail" {
+		os.Exit(1)
+	}
+
+	listeners, err := Inherit("api")
+	if err != nil || listeners["api"] == nil {
+		os.Exit(2)
+	}
+	if err := Ready(); err != nil {
+		os.Exit(3)
+	}
+	conn, err := listeners["api"].Accept()
+	if err != nil {
+		os.Exit(4)
+	}
+	conn.Write([]byte("child\n"))
+	conn.Close()
+	os.Exit(0)
+}
+
+func TestUpgrade(t *testing.T) {
+	if UpgradeSignal == nil {
+		t.Skip("upgrade is not supported on this platform")
+	}
+
+	tests := []struct {
+		name    string
+		mode    string
+		wantErr string
+	}{
+		{name: "child takes over the listener", mode: "serve"},
+		{name: "child exits before ready", mode: "fail", wantErr: "new process exited before becoming ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer ln.Close()
+			t.Setenv(helperEnv, tt.mode)
+
+			opts := DefaultUpgradeOptions
+			opts.Listeners = map[string]net.Listener{"api": ln}
+			opts.ReadyTimeout = 10 * time.Second
+			opts.Args = []string{"-test.run=^TestHelperProcess$"}
+			proc, err := Upgrade(opts)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// 旧进程停止接受连接后，新连接由新进程处理
+			ln.Close()
+			conn, err := net.Dial("tcp", ln.Addr().String())
+			require.NoError(t, err)
+			defer conn.Close()
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			require.NoError(t, err)
+			assert.Equal(t, "child\n", line)
+
+			state, err := proc.Wait()
+			require.NoError(t, err)
+			assert.True(t, state.Success())
+		})
+	}
+}
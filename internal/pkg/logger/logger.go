@@ -1,9 +1,11 @@
 package logger
 
 import (
+	"context"
 	"errors"
 	"os"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -17,6 +19,7 @@ var (
 // Logger 封装了zap.Logger
 type Logger struct {
 	logger *zap.Logger
+	level  zap.AtomicLevel // 由 New 创建的日志记录器及其派生的记录器共享，可在运行时调整
 }
 
 // LogConfig 定义了日志配置选项
@@ -32,10 +35,11 @@ type LogConfig struct {
 // New 创建一个新的日志记录器
 func New(opts LogConfig) (*Logger, error) {
 	// 解析日志级别
-	level, err := parseLogLevel(opts.Level)
+	parsed, err := parseLogLevel(opts.Level)
 	if err != nil {
 		return nil, err
 	}
+	level := zap.NewAtomicLevelAt(parsed)
 
 	// 创建编码器配置
 	encoderConfig := zapcore.EncoderConfig{
@@ -83,7 +87,17 @@ func New(opts LogConfig) (*Logger, error) {
 	// 创建日志记录器
 	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
 
-	return &Logger{logger: logger}, nil
+	return &Logger{logger: logger, level: level}, nil
+}
+
+// NewFromZap 使用已有的 zap.Logger 创建日志记录器，如测试中的 observer
+func NewFromZap(logger *zap.Logger) *Logger {
+	return &Logger{logger: logger, level: zap.NewAtomicLevel()}
+}
+
+// NewNop 创建一个不输出任何内容的日志记录器
+func NewNop() *Logger {
+	return &Logger{logger: zap.NewNop(), level: zap.NewAtomicLevel()}
 }
 
 // Debug 记录调试级别的日志
@@ -113,7 +127,35 @@ func (l *Logger) Fatal(msg string, fields ...zap.Field) {
 
 // With 创建一个带有额外字段的日志记录器
 func (l *Logger) With(fields ...zap.Field) *Logger {
-	return &Logger{logger: l.logger.With(fields...)}
+	return &Logger{logger: l.logger.With(fields...), level: l.level}
+}
+
+// SetLevel 在运行时调整日志级别，对所有派生的日志记录器生效
+// 通过 NewFromZap 创建的日志记录器级别由原 zap.Logger 决定，调用不会生效
+func (l *Logger) SetLevel(level string) error {
+	parsed, err := parseLogLevel(level)
+	if err != nil {
+		return err
+	}
+	l.level.SetLevel(parsed)
+	return nil
+}
+
+// Level 返回当前的日志级别
+func (l *Logger) Level() string {
+	return l.level.Level().String()
+}
+
+// WithContext 创建一个带有上下文链路信息（trace_id、span_id）的日志记录器
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return l
+	}
+	return l.With(
+		zap.String("trace_id", sc.TraceID().String()),
+		zap.String("span_id", sc.SpanID().String()),
+	)
 }
 
 // Sync 同步日志缓冲区
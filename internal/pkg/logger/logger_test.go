@@ -1,8 +1,11 @@
 package logger
 
 import (
+	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 )
@@ -49,3 +52,19 @@ func TestLoggerOptions(t *testing.T) {
 
 	logger.Info("test message")
 }
+
+func TestLoggerSetLevel(t *testing.T) {
+	logger, err := New(LogConfig{Level: "info", Filename: filepath.Join(t.TempDir(), "test.log")})
+	require.NoError(t, err)
+	derived := logger.With(zap.String("component", "test"))
+	assert.False(t, derived.logger.Core().Enabled(zap.DebugLevel))
+
+	// 调整级别对派生的日志记录器同样生效
+	require.NoError(t, logger.SetLevel("debug"))
+	assert.Equal(t, "debug", derived.Level())
+	assert.True(t, derived.logger.Core().Enabled(zap.DebugLevel))
+
+	// 无效的级别保留原有级别
+	assert.ErrorIs(t, logger.SetLevel("verbose"), ErrInvalidLogLevel)
+	assert.Equal(t, "debug", logger.Level())
+}
@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-api-mono/internal/pkg/concurrency"
+	"go-api-mono/internal/pkg/core"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// UnmatchedRoute 未匹配到路由时使用的标签值
+const UnmatchedRoute = "unmatched"
+
+// OtherMethod 非标准请求方法使用的标签值
+const OtherMethod = "OTHER"
+
+// standardMethods 按原样作为标签值的请求方法
+var standardMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// Metrics 应用程序的 Prometheus 指标
+// 使用独立的注册表，避免与第三方库注册到默认注册表的指标混在一起
+type Metrics struct {
+	registry    *prometheus.Registry
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	inFlight    *prometheus.GaugeVec
+	rateLimited *prometheus.CounterVec
+	panics      *prometheus.CounterVec
+}
+
+// New 创建指标集合，并注册 Go 运行时与进程指标
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests by route, method and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route and method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "Number of HTTP requests currently being served.",
+		}, []string{"method", "route"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_rate_limited_total",
+			Help: "Total number of requests rejected by the rate limiter.",
+		}, []string{"route"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_panics_recovered_total",
+			Help: "Total number of panics recovered while serving HTTP requests.",
+		}, []string{"route"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.inFlight,
+		m.rateLimited,
+		m.panics,
+	)
+	return m
+}
+
+// RegisterDB 注册数据库连接池指标
+func (m *Metrics) RegisterDB(name string, db *sql.DB) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// RateLimiterStats 内存限流器的统计信息
+type RateLimiterStats interface {
+	Len() int
+	Evictions() uint64
+}
+
+// RegisterRateLimiter 注册内存限流器的键数量与淘汰次数指标
+func (m *Metrics) RegisterRateLimiter(stats RateLimiterStats) error {
+	return m.Register(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "ratelimit_buckets",
+			Help: "Number of rate limit buckets held in memory.",
+		}, func() float64 { return float64(stats.Len()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "ratelimit_bucket_evictions_total",
+			Help: "Total number of rate limit buckets evicted by the size limit or idle cleanup.",
+		}, func() float64 { return float64(stats.Evictions()) }),
+	)
+}
+
+// ConcurrencyStats 并发限制器的统计信息
+type ConcurrencyStats interface {
+	Limit() int
+	InFlight() int
+	Rejected(priority concurrency.Priority) uint64
+}
+
+// RegisterConcurrencyLimiter 注册并发上限、并发请求数与按优先级统计的拒绝次数指标
+func (m *Metrics) RegisterConcurrencyLimiter(stats ConcurrencyStats) error {
+	cs := []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "concurrency_limit",
+			Help: "Current adaptive concurrency limit.",
+		}, func() float64 { return float64(stats.Limit()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "concurrency_in_flight",
+			Help: "Number of requests holding a concurrency slot.",
+		}, func() float64 { return float64(stats.InFlight()) }),
+	}
+	for _, priority := range concurrency.Priorities() {
+		cs = append(cs, prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "concurrency_rejected_total",
+			Help:        "Total number of requests shed by the concurrency limiter.",
+			ConstLabels: prometheus.Labels{"priority": priority.String()},
+		}, func() float64 { return float64(stats.Rejected(priority)) }))
+	}
+	return m.Register(cs...)
+}
+
+// Register 注册自定义指标
+func (m *Metrics) Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := m.registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler 返回 Prometheus 文本格式的指标处理器
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		Registry: m.registry,
+	})
+}
+
+// StartRequest 记录一个开始处理的请求，返回的函数在请求结束时调用
+// 非标准的请求方法统一记为 OtherMethod，未匹配路由的请求同样经过指标中间件，避免任意方法产生无限的标签组合
+func (m *Metrics) StartRequest(method, route string) func(status int) {
+	start := time.Now()
+	method = MethodLabel(method)
+	gauge := m.inFlight.WithLabelValues(method, route)
+	gauge.Inc()
+
+	return func(status int) {
+		gauge.Dec()
+		m.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	}
+}
+
+// RateLimited 记录一次被限流拒绝的请求
+func (m *Metrics) RateLimited(r *http.Request) {
+	m.rateLimited.WithLabelValues(RouteLabel(r)).Inc()
+}
+
+// PanicRecovered 记录一次被恢复的 panic
+func (m *Metrics) PanicRecovered(r *http.Request) {
+	m.panics.WithLabelValues(RouteLabel(r)).Inc()
+}
+
+// RouteLabel 返回请求匹配的路由模式，使用模式而非原始路径以限制标签基数
+func RouteLabel(r *http.Request) string {
+	if route := core.CurrentRoute(r.Context()); route != nil {
+		return route.Path
+	}
+	return UnmatchedRoute
+}
+
+// MethodLabel 返回请求方法的标签值，非标准方法返回 OtherMethod 以限制标签基数
+func MethodLabel(method string) string {
+	if standardMethods[method] {
+		return method
+	}
+	return OtherMethod
+}
+
+// Registry 返回指标注册表
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
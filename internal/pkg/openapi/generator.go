@@ -0,0 +1,224 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-api-mono/internal/pkg/core"
+)
+
+// Version 生成的 OpenAPI 版本
+const Version = "3.1.0"
+
+// Options 文档生成选项
+type Options struct {
+	Info            Info
+	Servers         []Server
+	SecuritySchemes map[string]*SecurityScheme
+}
+
+// generator 文档生成器
+type generator struct {
+	doc *Document
+}
+
+// Generate 根据已注册的路由生成 OpenAPI 文档
+func Generate(routes []*core.Route, opts Options) *Document {
+	g := &generator{
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    opts.Info,
+			Servers: opts.Servers,
+			Paths:   make(map[string]*PathItem),
+			Components: Components{
+				Schemas:         make(map[string]*Schema),
+				SecuritySchemes: opts.SecuritySchemes,
+			},
+		},
+	}
+	g.doc.Components.Schemas["Error"] = errorSchema()
+
+	tags := make(map[string]struct{})
+	for _, route := range routes {
+		if route.Hidden {
+			continue
+		}
+		path := strings.ReplaceAll(route.Path, "...}", "}")
+		item, ok := g.doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			g.doc.Paths[path] = item
+		}
+		setOperation(item, route.Method, g.operation(route, path))
+		for _, tag := range route.Tags {
+			tags[tag] = struct{}{}
+		}
+	}
+
+	for tag := range tags {
+		g.doc.Tags = append(g.doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(g.doc.Tags, func(i, j int) bool { return g.doc.Tags[i].Name < g.doc.Tags[j].Name })
+
+	return g.doc
+}
+
+// operation 生成单个路由的操作描述
+func (g *generator) operation(route *core.Route, path string) *Operation {
+	op := &Operation{
+		OperationID: operationID(route.Method, path),
+		Summary:     route.Summary,
+		Description: route.Description,
+		Tags:        route.Tags,
+		Parameters:  parameters(route),
+		Responses:   make(map[string]*Response),
+		Deprecated:  route.IsDeprecated(),
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: g.schemaFor(route.Request)},
+			},
+		}
+	}
+
+	for _, scheme := range route.Security {
+		op.Security = append(op.Security, map[string][]string{scheme: {}})
+	}
+
+	if len(route.Responses) == 0 {
+		op.Responses["200"] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+	for status, typ := range route.Responses {
+		op.Responses[strconv.Itoa(status)] = g.response(status, typ)
+	}
+	op.Responses["default"] = &Response{
+		Description: "Error",
+		Content: map[string]*MediaType{
+			"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}},
+		},
+	}
+
+	return op
+}
+
+// response 生成成功响应，数据包装在统一的响应结构中
+func (g *generator) response(status int, typ reflect.Type) *Response {
+	resp := &Response{Description: http.StatusText(status)}
+	if status == http.StatusNoContent {
+		return resp
+	}
+
+	var data *Schema
+	if typ != nil {
+		data = g.schemaFor(typ)
+	}
+	resp.Content = map[string]*MediaType{
+		"application/json": {Schema: envelopeSchema(data)},
+	}
+	return resp
+}
+
+// parameters 生成路径与查询参数，未显式描述的路径参数默认为字符串
+func parameters(route *core.Route) []Parameter {
+	var params []Parameter
+	described := make(map[string]bool)
+	for _, p := range route.Params {
+		described[p.In+":"+p.Name] = true
+		typ := p.Type
+		if typ == "" {
+			typ = "string"
+		}
+		params = append(params, Parameter{
+			Name:        p.Name,
+			In:          p.In,
+			Description: p.Description,
+			Required:    p.Required || p.In == "path",
+			Schema:      &Schema{Type: typ},
+		})
+	}
+	for _, name := range route.PathParams() {
+		if described["path:"+name] {
+			continue
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	return params
+}
+
+// envelopeSchema 统一响应结构 core.Response 的 Schema
+func envelopeSchema(data *Schema) *Schema {
+	schema := &Schema{
+		Type:     "object",
+		Required: []string{"code", "message"},
+		Properties: map[string]*Schema{
+			"code":       {Type: "integer", Description: "状态码"},
+			"message":    {Type: "string", Description: "响应信息"},
+			"trace_id":   {Type: "string", Description: "请求追踪ID"},
+			"request_id": {Type: "string", Description: "请求ID"},
+		},
+	}
+	if data != nil {
+		schema.Properties["data"] = data
+	}
+	return schema
+}
+
+// errorSchema 错误响应的 Schema
+func errorSchema() *Schema {
+	return &Schema{
+		Type:     "object",
+		Required: []string{"code", "message"},
+		Properties: map[string]*Schema{
+			"code":       {Type: "integer", Description: "错误码"},
+			"message":    {Type: "string", Description: "错误信息"},
+			"details":    {Description: "错误详情"},
+			"trace_id":   {Type: "string", Description: "请求追踪ID"},
+			"request_id": {Type: "string", Description: "请求ID"},
+		},
+	}
+}
+
+// setOperation 按请求方法设置操作
+func setOperation(item *PathItem, method string, op *Operation) {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		item.Get = op
+	case http.MethodPut:
+		item.Put = op
+	case http.MethodPost:
+		item.Post = op
+	case http.MethodDelete:
+		item.Delete = op
+	case http.MethodOptions:
+		item.Options = op
+	case http.MethodHead:
+		item.Head = op
+	case http.MethodPatch:
+		item.Patch = op
+	}
+}
+
+// operationID 根据请求方法与路径生成操作ID，如 GET /api/v1/users/{id} -> getApiV1UsersById
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "" {
+			continue
+		}
+		if strings.HasPrefix(seg, "{") {
+			b.WriteString("By")
+			seg = strings.Trim(seg, "{}")
+		}
+		for _, part := range strings.FieldsFunc(seg, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"go-api-mono/internal/pkg/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	ID        uint      `json:"id"`
+	Email     string    `json:"email" validate:"required,email"`
+	Name      string    `json:"name" validate:"required,min=3,max=32"`
+	Role      string    `json:"role,omitempty" validate:"oneof=admin user"`
+	Password  string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func TestGenerate(t *testing.T) {
+	routes := []*core.Route{
+		{Method: http.MethodPut, Path: "/api/v1/users/{id}", Security: []string{"BearerAuth"}},
+		{Method: http.MethodGet, Path: "/openapi.json", Hidden: true},
+	}
+	core.PathParam("id", "integer", "用户ID")(routes[0])
+	core.RequestBody(testUser{})(routes[0])
+	core.ResponseBody(http.StatusOK, testUser{})(routes[0])
+
+	doc := Generate(routes, Options{Info: Info{Title: "test", Version: "v1"}})
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Len(t, doc.Paths, 1)
+
+	op := doc.Paths["/api/v1/users/{id}"].Put
+	assert.NotNil(t, op)
+	assert.Equal(t, "putApiV1UsersById", op.OperationID)
+	assert.Equal(t, []map[string][]string{{"BearerAuth": {}}}, op.Security)
+	assert.Len(t, op.Parameters, 1)
+	assert.Equal(t, "integer", op.Parameters[0].Schema.Type)
+	assert.Equal(t, "#/components/schemas/testUser", op.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/testUser",
+		op.Responses["200"].Content["application/json"].Schema.Properties["data"].Ref)
+	assert.NotNil(t, op.Responses["default"])
+
+	schema := doc.Components.Schemas["testUser"]
+	assert.ElementsMatch(t, []string{"email", "name"}, schema.Required)
+	assert.NotContains(t, schema.Properties, "password")
+	assert.NotContains(t, schema.Properties, "Password")
+	assert.Equal(t, "email", schema.Properties["email"].Format)
+	assert.Equal(t, 3, *schema.Properties["name"].MinLength)
+	assert.Equal(t, 32, *schema.Properties["name"].MaxLength)
+	assert.Equal(t, []interface{}{"admin", "user"}, schema.Properties["role"].Enum)
+	assert.Equal(t, "date-time", schema.Properties["created_at"].Format)
+	assert.Equal(t, "integer", schema.Properties["id"].Type)
+}
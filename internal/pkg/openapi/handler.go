@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"embed"
+	"encoding/json"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+//go:embed ui.html
+var uiHTML string
+
+// uiAssets 内置的 Swagger UI 静态资源，版本见 swagger-ui/README.md
+//
+//go:embed swagger-ui/swagger-ui-bundle.js swagger-ui/swagger-ui.css
+var uiAssets embed.FS
+
+// uiTemplate Swagger UI 页面模板
+var uiTemplate = template.Must(template.New("ui").Parse(uiHTML))
+
+// Handler 返回以 JSON 格式提供文档的处理器
+func Handler(doc *Document) (http.Handler, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}), nil
+}
+
+// UIHandler 返回 Swagger UI 页面处理器，页面从 assetsURL 加载 UIAssetsHandler 提供的静态资源
+// nonce 用于获取当前请求 CSP 中的 nonce，为 nil 或返回空字符串时页面中的脚本不携带 nonce
+func UIHandler(title, specURL, assetsURL string, nonce func(*http.Request) string) http.Handler {
+	assetsURL = strings.TrimSuffix(assetsURL, "/")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := struct {
+			Title     string
+			SpecURL   string
+			AssetsURL string
+			Nonce     string
+		}{Title: title, SpecURL: specURL, AssetsURL: assetsURL}
+		if nonce != nil {
+			data.Nonce = nonce(r)
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = uiTemplate.Execute(w, data)
+	})
+}
+
+// UIAssetsHandler 返回内置 Swagger UI 静态资源的处理器，请求路径去掉 prefix 后为文件名
+func UIAssetsHandler(prefix string) http.Handler {
+	assets, _ := fs.Sub(uiAssets, "swagger-ui")
+	files := http.StripPrefix(prefix, http.FileServer(http.FS(assets)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,47 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIHandler(t *testing.T) {
+	nonce := func(*http.Request) string { return "abc" }
+	w := httptest.NewRecorder()
+	UIHandler("Test API", "/openapi.json", "/docs/assets/", nonce).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	// 页面只引用内置的静态资源
+	body := w.Body.String()
+	assert.Contains(t, body, `href="/docs/assets/swagger-ui.css"`)
+	assert.Contains(t, body, `<script nonce="abc" src="/docs/assets/swagger-ui-bundle.js">`)
+	assert.NotContains(t, body, "https://")
+}
+
+func TestUIAssetsHandler(t *testing.T) {
+	handler := UIAssetsHandler("/docs/assets/")
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+		expectedType   string
+	}{
+		{path: "/docs/assets/swagger-ui-bundle.js", expectedStatus: http.StatusOK, expectedType: "text/javascript; charset=utf-8"},
+		{path: "/docs/assets/swagger-ui.css", expectedStatus: http.StatusOK, expectedType: "text/css; charset=utf-8"},
+		{path: "/docs/assets/README.md", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedType != "" {
+				assert.Equal(t, tt.expectedType, w.Header().Get("Content-Type"))
+				assert.NotZero(t, w.Body.Len())
+			}
+		})
+	}
+}
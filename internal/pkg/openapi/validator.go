@@ -0,0 +1,344 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldError 描述一处校验失败
+type FieldError struct {
+	In      string `json:"in"`      // 位置：path、query、header、body 或 response
+	Pointer string `json:"pointer"` // 指向出错位置的 JSON Pointer（RFC 6901）
+	Message string `json:"message"` // 错误说明
+}
+
+// Load 解析 YAML 或 JSON 格式的 OpenAPI 文档
+func Load(data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi document: %w", err)
+	}
+	if doc.OpenAPI == "" {
+		return nil, fmt.Errorf("invalid openapi document: missing openapi version")
+	}
+	return &doc, nil
+}
+
+// Validator 按 OpenAPI 文档校验请求与响应
+type Validator struct {
+	doc      *Document
+	patterns sync.Map // pattern 字符串 -> *regexp.Regexp
+}
+
+// NewValidator 创建校验器
+func NewValidator(doc *Document) *Validator {
+	return &Validator{doc: doc}
+}
+
+// Operation 查找路由对应的操作，path 为路由模式（如 /api/v1/users/{id}）
+func (v *Validator) Operation(method, path string) *Operation {
+	item, ok := v.doc.Paths[path]
+	if !ok {
+		return nil
+	}
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		return item.Get
+	case http.MethodPut:
+		return item.Put
+	case http.MethodPost:
+		return item.Post
+	case http.MethodDelete:
+		return item.Delete
+	case http.MethodOptions:
+		return item.Options
+	case http.MethodHead:
+		return item.Head
+	case http.MethodPatch:
+		return item.Patch
+	}
+	return nil
+}
+
+// ValidateRequest 校验请求参数与请求体
+func (v *Validator) ValidateRequest(op *Operation, r *http.Request, body []byte) []FieldError {
+	var errs []FieldError
+
+	for _, p := range op.Parameters {
+		var value string
+		var present bool
+		switch p.In {
+		case "path":
+			value = r.PathValue(p.Name)
+			present = value != ""
+		case "query":
+			values, ok := r.URL.Query()[p.Name]
+			present = ok
+			if ok && len(values) > 0 {
+				value = values[0]
+			}
+		case "header":
+			value = r.Header.Get(p.Name)
+			present = value != ""
+		default:
+			continue
+		}
+
+		pointer := "/" + escapePointer(p.Name)
+		if !present {
+			if p.Required {
+				errs = append(errs, FieldError{In: p.In, Pointer: pointer, Message: "is required"})
+			}
+			continue
+		}
+		if p.Schema == nil {
+			continue
+		}
+		parsed, err := coerceParam(value, p.Schema.Type)
+		if err != nil {
+			errs = append(errs, FieldError{In: p.In, Pointer: pointer, Message: err.Error()})
+			continue
+		}
+		errs = append(errs, v.validate(p.In, pointer, p.Schema, parsed)...)
+	}
+
+	if op.RequestBody != nil {
+		media := op.RequestBody.Content["application/json"]
+		if len(bytes.TrimSpace(body)) == 0 {
+			if op.RequestBody.Required {
+				errs = append(errs, FieldError{In: "body", Pointer: "", Message: "request body is required"})
+			}
+		} else if media != nil && media.Schema != nil {
+			value, err := decodeJSON(body)
+			if err != nil {
+				errs = append(errs, FieldError{In: "body", Pointer: "", Message: "invalid JSON: " + err.Error()})
+			} else {
+				errs = append(errs, v.validate("body", "", media.Schema, value)...)
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateResponse 校验响应体
+func (v *Validator) ValidateResponse(op *Operation, status int, body []byte) []FieldError {
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		return []FieldError{{In: "response", Message: fmt.Sprintf("status %d is not documented", status)}}
+	}
+
+	media := resp.Content["application/json"]
+	if media == nil || media.Schema == nil {
+		return nil
+	}
+	value, err := decodeJSON(body)
+	if err != nil {
+		return []FieldError{{In: "response", Message: "invalid JSON: " + err.Error()}}
+	}
+	return v.validate("response", "", media.Schema, value)
+}
+
+// validate 按 Schema 递归校验值
+func (v *Validator) validate(in, pointer string, schema *Schema, value interface{}) []FieldError {
+	if schema.Ref != "" {
+		resolved := v.resolve(schema.Ref)
+		if resolved == nil {
+			return []FieldError{{In: in, Pointer: pointer, Message: "unresolvable schema reference " + schema.Ref}}
+		}
+		schema = resolved
+	}
+
+	fail := func(format string, args ...interface{}) []FieldError {
+		return []FieldError{{In: in, Pointer: pointer, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		return fail("must be one of %v", schema.Enum)
+	}
+
+	switch schema.Type {
+	case "":
+		return nil
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fail("must be an object")
+		}
+		var errs []FieldError
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				errs = append(errs, FieldError{In: in, Pointer: pointer + "/" + escapePointer(name), Message: "is required"})
+			}
+		}
+		for name, val := range obj {
+			child := pointer + "/" + escapePointer(name)
+			if prop, ok := schema.Properties[name]; ok {
+				errs = append(errs, v.validate(in, child, prop, val)...)
+			} else if schema.AdditionalProperties != nil {
+				errs = append(errs, v.validate(in, child, schema.AdditionalProperties, val)...)
+			}
+		}
+		return errs
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fail("must be an array")
+		}
+		var errs []FieldError
+		if schema.Items != nil {
+			for i, item := range arr {
+				errs = append(errs, v.validate(in, pointer+"/"+strconv.Itoa(i), schema.Items, item)...)
+			}
+		}
+		return errs
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fail("must be a string")
+		}
+		n := utf8.RuneCountInString(s)
+		if schema.MinLength != nil && n < *schema.MinLength {
+			return fail("must be at least %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && n > *schema.MaxLength {
+			return fail("must be at most %d characters", *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			re, err := v.pattern(schema.Pattern)
+			if err == nil && !re.MatchString(s) {
+				return fail("must match pattern %s", schema.Pattern)
+			}
+		}
+		if msg := checkFormat(schema.Format, s); msg != "" {
+			return fail("%s", msg)
+		}
+		return nil
+	case "integer", "number":
+		num, ok := value.(json.Number)
+		if !ok {
+			return fail("must be a %s", schema.Type)
+		}
+		f, err := num.Float64()
+		if err != nil {
+			return fail("must be a %s", schema.Type)
+		}
+		if schema.Type == "integer" {
+			if _, err := num.Int64(); err != nil {
+				return fail("must be an integer")
+			}
+		}
+		if schema.Minimum != nil && f < *schema.Minimum {
+			return fail("must be greater than or equal to %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			return fail("must be less than or equal to %v", *schema.Maximum)
+		}
+		return nil
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail("must be a boolean")
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// resolve 解析组件引用
+func (v *Validator) resolve(ref string) *Schema {
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	return v.doc.Components.Schemas[name]
+}
+
+// pattern 编译并缓存正则表达式
+func (v *Validator) pattern(p string) (*regexp.Regexp, error) {
+	if re, ok := v.patterns.Load(p); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return nil, err
+	}
+	v.patterns.Store(p, re)
+	return re, nil
+}
+
+// decodeJSON 解码 JSON，数字保留为 json.Number 以区分整数
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// coerceParam 将字符串参数转换为 Schema 类型对应的值
+func coerceParam(value, typ string) (interface{}, error) {
+	switch typ {
+	case "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return json.Number(value), nil
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return json.Number(value), nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return b, nil
+	default:
+		return value, nil
+	}
+}
+
+// checkFormat 校验字符串格式，返回错误说明
+func checkFormat(format, s string) string {
+	switch format {
+	case "email":
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			return "must be a valid email address"
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return "must be an RFC 3339 date-time"
+		}
+	}
+	return ""
+}
+
+// inEnum 判断值是否在枚举中
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// escapePointer 按 RFC 6901 转义 JSON Pointer 片段
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
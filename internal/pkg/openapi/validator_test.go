@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSpec = `
+openapi: 3.1.0
+info:
+  title: test
+  version: v1
+paths:
+  /users/{id}:
+    put:
+      operationId: putUsersById
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: notify
+          in: query
+          schema:
+            type: boolean
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      required: [email, username]
+      properties:
+        email:
+          type: string
+          format: email
+        username:
+          type: string
+          minLength: 3
+        role:
+          type: string
+          enum: [admin, user]
+        tags:
+          type: array
+          items:
+            type: string
+`
+
+func TestValidateRequest(t *testing.T) {
+	doc, err := Load([]byte(testSpec))
+	assert.NoError(t, err)
+	v := NewValidator(doc)
+	op := v.Operation(http.MethodPut, "/users/{id}")
+	assert.NotNil(t, op)
+
+	tests := []struct {
+		name     string
+		id       string
+		query    string
+		body     string
+		pointers []string
+	}{
+		{name: "合法请求", id: "1", query: "notify=true", body: `{"email":"a@example.com","username":"alice","tags":["x"]}`},
+		{name: "路径参数类型错误", id: "abc", body: `{"email":"a@example.com","username":"alice"}`, pointers: []string{"/id"}},
+		{name: "查询参数类型错误", id: "1", query: "notify=maybe", body: `{"email":"a@example.com","username":"alice"}`, pointers: []string{"/notify"}},
+		{name: "缺少必填字段", id: "1", body: `{"email":"a@example.com"}`, pointers: []string{"/username"}},
+		{name: "字段类型与约束", id: "1", body: `{"email":"bad","username":"al","role":"root","tags":[1]}`,
+			pointers: []string{"/email", "/username", "/role", "/tags/0"}},
+		{name: "缺少请求体", id: "1", body: ``, pointers: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/users/"+tt.id+"?"+tt.query, strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.id)
+
+			errs := v.ValidateRequest(op, req, []byte(tt.body))
+			var pointers []string
+			for _, e := range errs {
+				pointers = append(pointers, e.Pointer)
+			}
+			assert.ElementsMatch(t, tt.pointers, pointers)
+		})
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	doc, err := Load([]byte(testSpec))
+	assert.NoError(t, err)
+	v := NewValidator(doc)
+	op := v.Operation(http.MethodPut, "/users/{id}")
+
+	assert.Empty(t, v.ValidateResponse(op, http.StatusOK, []byte(`{"email":"a@example.com","username":"alice"}`)))
+	assert.NotEmpty(t, v.ValidateResponse(op, http.StatusOK, []byte(`{"email":"a@example.com"}`)))
+	assert.NotEmpty(t, v.ValidateResponse(op, http.StatusTeapot, []byte(`{}`)))
+}
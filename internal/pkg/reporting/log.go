@@ -0,0 +1,47 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-api-mono/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// LogReporter 仅将事件写入日志的上报器
+type LogReporter struct {
+	logger *logger.Logger
+}
+
+// NewLogReporter 创建日志上报器
+func NewLogReporter(log *logger.Logger) *LogReporter {
+	return &LogReporter{logger: log}
+}
+
+// Report 实现 Reporter 接口
+func (l *LogReporter) Report(ctx context.Context, event *Event) {
+	fields := []zap.Field{
+		zap.String("error", event.Message),
+		zap.String("method", event.Request.Method),
+		zap.String("url", event.Request.URL),
+		zap.String("route", event.Request.Route),
+		zap.String("client_ip", event.Request.ClientIP),
+		zap.String("request_id", event.Request.RequestID),
+		zap.String("stack", FormatStack(event.Stack)),
+	}
+	if event.User != nil {
+		fields = append(fields, zap.String("user_id", event.User.ID))
+	}
+	l.logger.WithContext(ctx).Error("Panic recovered", fields...)
+}
+
+// FormatStack 将调用栈格式化为与 runtime/debug.Stack 类似的文本
+func FormatStack(stack []Frame) string {
+	var b strings.Builder
+	for _, f := range stack {
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+	}
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// SensitiveFields 请求体中始终隐藏取值的字段（不区分大小写、忽略 "_" 与 "-" 的子串匹配）
+var SensitiveFields = []string{"password", "secret", "token", "authorization", "apikey"}
+
+// RedactJSON 返回隐藏了敏感字段的 JSON
+// paths 为额外需要隐藏的字段路径，如 "$.card.number"、"items[*].serial"，"*" 匹配任意字段或数组元素；
+// 无法解析的 JSON（如截断的请求体）只按字段名隐藏 SensitiveFields 与 paths 的最后一段
+func RedactJSON(data []byte, paths []string) []byte {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return redactJSONText(data, paths)
+	}
+
+	v = redactValue(v, nil, parsePaths(paths))
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return redactJSONText(data, paths)
+	}
+	return redacted
+}
+
+// RedactForm 返回隐藏了敏感字段的 application/x-www-form-urlencoded 请求体
+func RedactForm(data []byte) []byte {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return []byte(Filtered)
+	}
+	for name := range values {
+		if isSensitiveField(name) {
+			values[name] = []string{Filtered}
+		}
+	}
+	return []byte(values.Encode())
+}
+
+// isSensitiveField 判断字段名是否包含敏感字样
+func isSensitiveField(name string) bool {
+	name = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+	for _, s := range SensitiveFields {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePaths 将字段路径拆分为段，"$." 前缀可省略，"[*]" 等价于 ".*"
+func parsePaths(paths []string) [][]string {
+	parsed := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+		path = strings.ReplaceAll(path, "[*]", ".*")
+		if path != "" {
+			parsed = append(parsed, strings.Split(path, "."))
+		}
+	}
+	return parsed
+}
+
+// matchPath 判断字段路径是否与任一路径完全匹配
+func matchPath(path []string, paths [][]string) bool {
+	for _, p := range paths {
+		if len(p) != len(path) {
+			continue
+		}
+		matched := true
+		for i := range p {
+			if p[i] != "*" && p[i] != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue 递归隐藏敏感字段，path 为当前值所在的字段路径
+func redactValue(v interface{}, path []string, paths [][]string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := append(path[:len(path):len(path)], key)
+			if isSensitiveField(key) || matchPath(childPath, paths) {
+				v[key] = Filtered
+				continue
+			}
+			v[key] = redactValue(child, childPath, paths)
+		}
+	case []interface{}:
+		for i, child := range v {
+			childPath := append(path[:len(path):len(path)], "*")
+			if matchPath(childPath, paths) {
+				v[i] = Filtered
+				continue
+			}
+			v[i] = redactValue(child, childPath, paths)
+		}
+	}
+	return v
+}
+
+// jsonField 匹配 JSON 文本中的字段及其取值，取值可以是字符串（允许被截断）或其他标量
+var jsonField = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)("(?:[^"\\]|\\.)*"?|[^\s,{}\[\]]+)`)
+
+// redactJSONText 在无法解析的 JSON 文本中按字段名隐藏取值
+func redactJSONText(data []byte, paths []string) []byte {
+	names := make(map[string]bool)
+	for _, p := range parsePaths(paths) {
+		names[p[len(p)-1]] = true
+	}
+	return jsonField.ReplaceAllFunc(data, func(m []byte) []byte {
+		sub := jsonField.FindSubmatch(m)
+		name := string(sub[1])
+		if !isSensitiveField(name) && !names[name] {
+			return m
+		}
+		return []byte(`"` + name + `"` + string(sub[2]) + `"` + Filtered + `"`)
+	})
+}
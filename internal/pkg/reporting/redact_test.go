@@ -0,0 +1,61 @@
+package reporting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		paths []string
+		want  string
+	}{
+		{
+			name: "始终隐藏敏感字段",
+			data: `{"username":"alice","password":"p","nested":{"accessToken":"t","Authorization":"Bearer x","api_key":"k"}}`,
+			want: `{"nested":{"Authorization":"[Filtered]","accessToken":"[Filtered]","api_key":"[Filtered]"},"password":"[Filtered]","username":"alice"}`,
+		},
+		{
+			name:  "按路径隐藏",
+			data:  `{"card":{"number":"4111","brand":"visa"},"number":1}`,
+			paths: []string{"$.card.number"},
+			want:  `{"card":{"brand":"visa","number":"[Filtered]"},"number":1}`,
+		},
+		{
+			name:  "通配数组元素",
+			data:  `{"items":[{"serial":"a","qty":1},{"serial":"b","qty":2}]}`,
+			paths: []string{"items[*].serial"},
+			want:  `{"items":[{"qty":1,"serial":"[Filtered]"},{"qty":2,"serial":"[Filtered]"}]}`,
+		},
+		{
+			name: "保留大整数",
+			data: `{"id":12345678901234567890}`,
+			want: `{"id":12345678901234567890}`,
+		},
+		{
+			name:  "截断的 JSON 按字段名隐藏",
+			data:  `{"username":"alice","password":"secr`,
+			paths: []string{"$.card.number"},
+			want:  `{"username":"alice","password":"[Filtered]"`,
+		},
+		{
+			name:  "截断的 JSON 按路径最后一段隐藏",
+			data:  `{"card":{"number": 4111, "brand":"vi`,
+			paths: []string{"$.card.number"},
+			want:  `{"card":{"number": "[Filtered]", "brand":"vi`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(RedactJSON([]byte(tt.data), tt.paths)))
+		})
+	}
+}
+
+func TestRedactForm(t *testing.T) {
+	assert.Equal(t, "password=%5BFiltered%5D&username=alice", string(RedactForm([]byte("username=alice&password=secret"))))
+}
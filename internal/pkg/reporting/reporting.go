@@ -0,0 +1,249 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-mono/internal/pkg/core"
+	"go-api-mono/internal/pkg/tracing"
+)
+
+// Filtered 替换敏感信息的占位符
+const Filtered = "[Filtered]"
+
+// SensitiveHeaders 上报时需要隐藏取值的请求头
+var SensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-API-Key",
+	"X-CSRF-Token",
+}
+
+// SensitiveParams 上报时需要隐藏取值的查询参数（不区分大小写的子串匹配）
+var SensitiveParams = []string{"password", "secret", "token", "key", "signature", "code"}
+
+// Reporter 错误上报接口，实现应当尽快返回，不阻塞请求处理
+type Reporter interface {
+	Report(ctx context.Context, event *Event)
+}
+
+// Event 一次需要上报的错误
+type Event struct {
+	Time    time.Time
+	Message string  // panic 的值或错误信息
+	Stack   []Frame // 调用栈，由内向外排列
+	Request Request
+	User    *User // 未认证的请求为 nil
+}
+
+// Frame 调用栈中的一帧
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Request 已隐藏敏感信息的请求元数据
+type Request struct {
+	Method    string
+	URL       string            // 查询参数中的敏感值已隐藏
+	Route     string            // 路由模式，未匹配路由时为空
+	Headers   map[string]string // 敏感请求头的取值已隐藏
+	ClientIP  string
+	RequestID string
+	TraceID   string
+	SpanID    string
+}
+
+// User 发生错误时的已认证用户
+type User struct {
+	ID       string
+	Username string
+	Role     string
+}
+
+// Panic 在其他协程中恢复并转交给当前协程重新抛出的 panic，保留原始值与引发 panic 时的调用栈
+type Panic struct {
+	Value interface{} // 原始 panic 的值
+	pcs   []uintptr
+}
+
+// NewPanic 包装恢复的 panic 值并记录调用栈
+// 必须在 recover 所在的延迟函数中调用，才能取得 panic 时的调用栈
+func NewPanic(recovered interface{}) *Panic {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	return &Panic{Value: recovered, pcs: pcs[:n]}
+}
+
+// String 返回原始 panic 的值，未被恢复时运行时按原始值输出
+func (p *Panic) String() string {
+	return fmt.Sprint(p.Value)
+}
+
+// Unwrap 返回恢复的 panic 的原始值，展开 Panic 包装
+func Unwrap(recovered interface{}) interface{} {
+	if p, ok := recovered.(*Panic); ok {
+		return p.Value
+	}
+	return recovered
+}
+
+// NewEvent 根据恢复的 panic 创建事件，调用栈从引发 panic 的函数开始
+// 必须在 recover 所在的延迟函数中调用，才能取得 panic 时的调用栈；
+// recovered 为 Panic 时使用其原始值与原协程的调用栈
+func NewEvent(r *http.Request, recovered interface{}) *Event {
+	var stack []Frame
+	if p, ok := recovered.(*Panic); ok {
+		recovered, stack = p.Value, panicFrames(p.pcs)
+	} else {
+		pcs := make([]uintptr, 64)
+		stack = panicFrames(pcs[:runtime.Callers(2, pcs)])
+	}
+	return &Event{
+		Time:    time.Now(),
+		Message: fmt.Sprint(recovered),
+		Stack:   stack,
+		Request: NewRequest(r),
+		User:    UserFromContext(r.Context()),
+	}
+}
+
+// panicFrames 将调用栈转换为帧列表，去掉 panic 处理相关的帧
+func panicFrames(pcs []uintptr) []Frame {
+	frames := runtime.CallersFrames(pcs)
+
+	var stack []Frame
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "runtime.gopanic" {
+			// 丢弃 recover 所在的延迟函数等帧
+			stack = stack[:0]
+		} else {
+			stack = append(stack, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// NewRequest 提取请求元数据并隐藏敏感信息
+func NewRequest(r *http.Request) Request {
+	req := Request{
+		Method:    r.Method,
+		URL:       RedactURL(r.URL),
+		Headers:   RedactHeaders(r.Header),
+		ClientIP:  core.ClientIP(r),
+		RequestID: core.RequestID(r.Context()),
+		TraceID:   tracing.TraceID(r.Context()),
+		SpanID:    tracing.SpanID(r.Context()),
+	}
+	if route := core.CurrentRoute(r.Context()); route != nil {
+		req.Route = route.Path
+	}
+	return req
+}
+
+// RedactHeaders 返回请求头的副本，敏感请求头的取值替换为占位符
+func RedactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		headers[name] = strings.Join(values, ", ")
+	}
+	for _, name := range SensitiveHeaders {
+		if _, ok := headers[http.CanonicalHeaderKey(name)]; ok {
+			headers[http.CanonicalHeaderKey(name)] = Filtered
+		}
+	}
+	return headers
+}
+
+// RedactURL 返回隐藏了敏感查询参数与用户信息的地址
+func RedactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+
+	query := redacted.Query()
+	for name := range query {
+		if isSensitiveParam(name) {
+			query[name] = []string{Filtered}
+		}
+	}
+	if len(query) > 0 {
+		redacted.RawQuery = query.Encode()
+	}
+	return redacted.String()
+}
+
+// isSensitiveParam 判断查询参数名是否包含敏感字样
+func isSensitiveParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range SensitiveParams {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Scope 请求范围内收集的上报信息
+// 由外层中间件创建，内层中间件（如认证）向其中写入用户，使外层在 panic 或记录访问日志时可以取得
+type Scope struct {
+	mu   sync.Mutex
+	user *User
+}
+
+// scopeKey Scope 的上下文键
+type scopeKey struct{}
+
+// WithScope 返回携带 Scope 的上下文，上下文中已有 Scope 时直接返回
+func WithScope(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(scopeKey{}).(*Scope); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, scopeKey{}, &Scope{})
+}
+
+// SetUser 记录当前请求的已认证用户，上下文中没有 Scope 时忽略
+func SetUser(ctx context.Context, user User) {
+	if scope, ok := ctx.Value(scopeKey{}).(*Scope); ok {
+		scope.mu.Lock()
+		scope.user = &user
+		scope.mu.Unlock()
+	}
+}
+
+// UserFromContext 返回通过 SetUser 记录的用户，未记录时返回 nil
+func UserFromContext(ctx context.Context) *User {
+	scope, ok := ctx.Value(scopeKey{}).(*Scope)
+	if !ok {
+		return nil
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	return scope.user
+}
+
+// Multi 将事件依次交给多个上报器
+func Multi(reporters ...Reporter) Reporter {
+	return multiReporter(reporters)
+}
+
+// multiReporter 组合多个上报器
+type multiReporter []Reporter
+
+// Report 实现 Reporter 接口
+func (m multiReporter) Report(ctx context.Context, event *Event) {
+	for _, r := range m {
+		r.Report(ctx, event)
+	}
+}
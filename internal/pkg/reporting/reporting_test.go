@@ -0,0 +1,102 @@
+package reporting
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-api-mono/internal/pkg/core"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequest(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "http://example.com/api/users?page=2&access_token=abc&apiKey=k", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("Cookie", "session=secret")
+	r.Header.Set("X-API-Key", "secret")
+	r.Header.Set("User-Agent", "test")
+	r = r.WithContext(core.WithRequestID(r.Context(), "req-1"))
+
+	req := NewRequest(r)
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "req-1", req.RequestID)
+	assert.Equal(t, "192.0.2.1", req.ClientIP)
+	assert.Equal(t, Filtered, req.Headers["Authorization"])
+	assert.Equal(t, Filtered, req.Headers["Cookie"])
+	assert.Equal(t, Filtered, req.Headers["X-Api-Key"])
+	assert.Equal(t, "test", req.Headers["User-Agent"])
+
+	assert.NotContains(t, req.URL, "abc")
+	assert.Contains(t, req.URL, "page=2")
+	assert.Contains(t, req.URL, "access_token=%5BFiltered%5D")
+	assert.Contains(t, req.URL, "apiKey=%5BFiltered%5D")
+}
+
+func TestScope(t *testing.T) {
+	// 没有 Scope 时忽略
+	SetUser(context.Background(), User{ID: "1"})
+	assert.Nil(t, UserFromContext(context.Background()))
+
+	ctx := WithScope(context.Background())
+	assert.Nil(t, UserFromContext(ctx))
+
+	// 内层上下文写入的用户对外层可见
+	inner := context.WithValue(ctx, struct{}{}, "inner")
+	SetUser(inner, User{ID: "42", Role: "admin"})
+	require.NotNil(t, UserFromContext(ctx))
+	assert.Equal(t, "42", UserFromContext(ctx).ID)
+}
+
+// panicking 引发 panic 的函数，用于检查调用栈的起点
+func panicking() {
+	panic("boom")
+}
+
+func TestNewEvent(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	var event *Event
+	func() {
+		defer func() {
+			if p := recover(); p != nil {
+				event = NewEvent(r, p)
+			}
+		}()
+		panicking()
+	}()
+
+	require.NotNil(t, event)
+	assert.Equal(t, "boom", event.Message)
+	require.NotEmpty(t, event.Stack)
+	assert.True(t, strings.HasSuffix(event.Stack[0].Function, "reporting.panicking"), event.Stack[0].Function)
+	assert.Contains(t, FormatStack(event.Stack), "reporting_test.go")
+}
+
+func TestNewEventPanic(t *testing.T) {
+	// 模拟在其他协程中恢复后重新抛出的 panic
+	var p *Panic
+	func() {
+		defer func() {
+			p = NewPanic(recover())
+		}()
+		panicking()
+	}()
+	assert.Equal(t, "boom", p.String())
+	assert.Equal(t, "boom", Unwrap(p))
+	assert.Equal(t, "boom", Unwrap("boom"))
+
+	var event *Event
+	func() {
+		defer func() {
+			event = NewEvent(httptest.NewRequest(http.MethodGet, "/", nil), recover())
+		}()
+		panic(p)
+	}()
+
+	assert.Equal(t, "boom", event.Message)
+	require.NotEmpty(t, event.Stack)
+	assert.True(t, strings.HasSuffix(event.Stack[0].Function, "reporting.panicking"), event.Stack[0].Function)
+}
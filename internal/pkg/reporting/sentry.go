@@ -0,0 +1,295 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-mono/internal/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// sentryClient 上报时使用的客户端标识
+const sentryClient = "go-api-mono/1.0"
+
+// SentryOptions Sentry 上报器选项
+type SentryOptions struct {
+	DSN         string        // 项目 DSN，如 https://<key>@sentry.example.com/<project>
+	Environment string        // 部署环境
+	Release     string        // 版本号
+	ServerName  string        // 主机名
+	Timeout     time.Duration // 单次发送的超时时间
+	MaxPending  int           // 同时发送中的事件上限，超出时丢弃新事件
+	Logger      *logger.Logger
+	Client      *http.Client // 为空时使用带超时的默认客户端
+}
+
+// DefaultSentryOptions 默认的 Sentry 上报器选项
+var DefaultSentryOptions = SentryOptions{
+	Timeout:    5 * time.Second,
+	MaxPending: 32,
+}
+
+// SentryReporter 以 Sentry envelope 格式通过 HTTP 上报事件的上报器
+// 事件在后台协程中发送，不阻塞请求处理；退出前调用 Flush 等待发送完成
+type SentryReporter struct {
+	opts      SentryOptions
+	endpoint  string
+	publicKey string
+	client    *http.Client
+	logger    *logger.Logger
+	pending   chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewSentryReporter 创建 Sentry 上报器
+func NewSentryReporter(opts SentryOptions) (*SentryReporter, error) {
+	u, err := url.Parse(opts.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sentry dsn: %w", err)
+	}
+	publicKey := u.User.Username()
+	idx := strings.LastIndex(u.Path, "/")
+	if u.Scheme == "" || u.Host == "" || publicKey == "" || idx < 0 || u.Path[idx+1:] == "" {
+		return nil, fmt.Errorf("invalid sentry dsn: expected scheme://key@host/project")
+	}
+	projectID := u.Path[idx+1:]
+
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultSentryOptions.Timeout
+	}
+	if opts.MaxPending <= 0 {
+		opts.MaxPending = DefaultSentryOptions.MaxPending
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: opts.Timeout}
+	}
+	log := opts.Logger
+	if log == nil {
+		log = logger.NewNop()
+	}
+
+	return &SentryReporter{
+		opts:      opts,
+		endpoint:  fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, u.Path[:idx], projectID),
+		publicKey: publicKey,
+		client:    client,
+		logger:    log,
+		pending:   make(chan struct{}, opts.MaxPending),
+	}, nil
+}
+
+// Report 实现 Reporter 接口，事件在后台发送
+func (s *SentryReporter) Report(_ context.Context, event *Event) {
+	body, err := s.envelope(event)
+	if err != nil {
+		s.logger.Error("Failed to encode sentry event", zap.Error(err))
+		return
+	}
+
+	select {
+	case s.pending <- struct{}{}:
+	default:
+		s.logger.Warn("Sentry event dropped, too many pending events")
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer func() {
+			<-s.pending
+			s.wg.Done()
+		}()
+		if err := s.send(body); err != nil {
+			s.logger.Warn("Failed to send sentry event", zap.Error(err))
+		}
+	}()
+}
+
+// Flush 等待已上报的事件发送完成，或直到上下文结束
+func (s *SentryReporter) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// send 发送 envelope
+func (s *SentryReporter) send(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s",
+		sentryClient, s.publicKey))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("sentry responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sentryEvent Sentry 事件负载
+type sentryEvent struct {
+	EventID     string                       `json:"event_id"`
+	Timestamp   string                       `json:"timestamp"`
+	Platform    string                       `json:"platform"`
+	Level       string                       `json:"level"`
+	Environment string                       `json:"environment,omitempty"`
+	Release     string                       `json:"release,omitempty"`
+	ServerName  string                       `json:"server_name,omitempty"`
+	Exception   sentryExceptions             `json:"exception"`
+	Request     sentryRequest                `json:"request"`
+	User        *sentryUser                  `json:"user,omitempty"`
+	Tags        map[string]string            `json:"tags,omitempty"`
+	Contexts    map[string]map[string]string `json:"contexts,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string           `json:"type"`
+	Value      string           `json:"value"`
+	Stacktrace sentryStacktrace `json:"stacktrace"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+type sentryUser struct {
+	ID        string `json:"id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	IPAddress string `json:"ip_address,omitempty"`
+	Role      string `json:"role,omitempty"`
+}
+
+// envelope 将事件编码为 Sentry envelope：头部、条目头与事件负载各占一行
+func (s *SentryReporter) envelope(event *Event) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	eventID := hex.EncodeToString(id)
+
+	// Sentry 要求调用栈由外向内排列
+	frames := make([]sentryFrame, 0, len(event.Stack))
+	for i := len(event.Stack) - 1; i >= 0; i-- {
+		f := event.Stack[i]
+		frames = append(frames, sentryFrame{
+			Function: f.Function,
+			AbsPath:  f.File,
+			Lineno:   f.Line,
+			InApp:    strings.HasPrefix(f.Function, "go-api-mono/"),
+		})
+	}
+
+	payload := sentryEvent{
+		EventID:     eventID,
+		Timestamp:   event.Time.UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       "fatal",
+		Environment: s.opts.Environment,
+		Release:     s.opts.Release,
+		ServerName:  s.opts.ServerName,
+		Exception: sentryExceptions{Values: []sentryException{{
+			Type:       "panic",
+			Value:      event.Message,
+			Stacktrace: sentryStacktrace{Frames: frames},
+		}}},
+		Request: sentryRequest{
+			Method:  event.Request.Method,
+			URL:     event.Request.URL,
+			Headers: event.Request.Headers,
+			Env:     map[string]string{"REMOTE_ADDR": event.Request.ClientIP},
+		},
+		Tags: map[string]string{
+			"route":      event.Request.Route,
+			"request_id": event.Request.RequestID,
+		},
+	}
+	if event.User != nil {
+		payload.User = &sentryUser{
+			ID:        event.User.ID,
+			Username:  event.User.Username,
+			IPAddress: event.Request.ClientIP,
+			Role:      event.User.Role,
+		}
+	}
+	if event.Request.TraceID != "" {
+		payload.Contexts = map[string]map[string]string{
+			"trace": {"trace_id": event.Request.TraceID, "span_id": event.Request.SpanID},
+		}
+	}
+
+	item, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	header, err := json.Marshal(map[string]string{
+		"event_id": eventID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339Nano),
+		"dsn":      s.opts.DSN,
+	})
+	if err != nil {
+		return nil, err
+	}
+	itemHeader, err := json.Marshal(map[string]interface{}{
+		"type":         "event",
+		"length":       len(item),
+		"content_type": "application/json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	for _, line := range [][]byte{header, itemHeader, item} {
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,117 @@
+package reporting
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sentryStandIn 模拟 Sentry 的 envelope 接口，记录收到的请求
+type sentryStandIn struct {
+	mu       sync.Mutex
+	paths    []string
+	auth     []string
+	payloads [][]byte
+}
+
+func (s *sentryStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paths = append(s.paths, r.URL.Path)
+	s.auth = append(s.auth, r.Header.Get("X-Sentry-Auth"))
+	s.payloads = append(s.payloads, body)
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestSentryReporter(t *testing.T) {
+	standIn := &sentryStandIn{}
+	srv := httptest.NewServer(standIn)
+	defer srv.Close()
+
+	dsn := strings.Replace(srv.URL, "http://", "http://public-key@", 1) + "/sentry/42"
+	reporter, err := NewSentryReporter(SentryOptions{DSN: dsn, Environment: "testing", Release: "v1.0.0"})
+	require.NoError(t, err)
+
+	reporter.Report(context.Background(), &Event{
+		Time:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Message: "boom",
+		Stack: []Frame{
+			{Function: "go-api-mono/internal/app.handler", File: "/src/app.go", Line: 10},
+			{Function: "net/http.HandlerFunc.ServeHTTP", File: "/go/server.go", Line: 20},
+		},
+		Request: Request{
+			Method:    http.MethodGet,
+			URL:       "/api/v1/users/1",
+			Route:     "/api/v1/users/{id}",
+			Headers:   map[string]string{"Authorization": Filtered},
+			ClientIP:  "203.0.113.1",
+			RequestID: "req-1",
+			TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		User: &User{ID: "7", Username: "alice"},
+	})
+	require.NoError(t, reporter.Flush(context.Background()))
+
+	standIn.mu.Lock()
+	defer standIn.mu.Unlock()
+	require.Len(t, standIn.payloads, 1)
+	assert.Equal(t, "/sentry/api/42/envelope/", standIn.paths[0])
+	assert.Contains(t, standIn.auth[0], "sentry_key=public-key")
+	assert.Contains(t, standIn.auth[0], "sentry_version=7")
+
+	// envelope 由头部、条目头与事件负载三行组成
+	scanner := bufio.NewScanner(strings.NewReader(string(standIn.payloads[0])))
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.Len(t, lines, 3)
+
+	var header map[string]string
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+	assert.Len(t, header["event_id"], 32)
+
+	var item struct {
+		Type   string `json:"type"`
+		Length int    `json:"length"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &item))
+	assert.Equal(t, "event", item.Type)
+	assert.Equal(t, len(lines[2]), item.Length)
+
+	var event sentryEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &event))
+	assert.Equal(t, header["event_id"], event.EventID)
+	assert.Equal(t, "testing", event.Environment)
+	assert.Equal(t, "v1.0.0", event.Release)
+	assert.Equal(t, "boom", event.Exception.Values[0].Value)
+	assert.Equal(t, Filtered, event.Request.Headers["Authorization"])
+	assert.Equal(t, "7", event.User.ID)
+	assert.Equal(t, "req-1", event.Tags["request_id"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", event.Contexts["trace"]["trace_id"])
+
+	// 调用栈由外向内排列，只有本项目的帧标记为 in_app
+	frames := event.Exception.Values[0].Stacktrace.Frames
+	require.Len(t, frames, 2)
+	assert.Equal(t, "net/http.HandlerFunc.ServeHTTP", frames[0].Function)
+	assert.False(t, frames[0].InApp)
+	assert.True(t, frames[1].InApp)
+}
+
+func TestNewSentryReporterInvalidDSN(t *testing.T) {
+	for _, dsn := range []string{"", "https://sentry.example.com/1", "https://key@sentry.example.com/", "not a url"} {
+		_, err := NewSentryReporter(SentryOptions{DSN: dsn})
+		assert.Error(t, err, dsn)
+	}
+}
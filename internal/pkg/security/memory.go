@@ -0,0 +1,209 @@
+package security
+
+import (
+	"container/list"
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// memoryShards 内存限流器的分片数
+const memoryShards = 32
+
+// MemoryOptions 内存限流器选项
+type MemoryOptions struct {
+	MaxEntries      int           // 最多保存的键数量，超出时淘汰最久未使用的键，0 表示不限制
+	IdleTTL         time.Duration // 键空闲超过该时长后被清理，0 表示不按空闲时间清理
+	CleanupInterval time.Duration // 清理空闲键的间隔，0 时使用 IdleTTL
+}
+
+// DefaultMemoryOptions 默认内存限流器选项
+var DefaultMemoryOptions = MemoryOptions{
+	MaxEntries:      100000,
+	IdleTTL:         10 * time.Minute,
+	CleanupInterval: time.Minute,
+}
+
+// limitState 内存限流器中单个键的状态
+type limitState interface {
+	take(now time.Time, limit Limit, cost int) Result
+}
+
+// memoryEntry 键及其限流状态，lastSeen 由所属分片的锁保护
+type memoryEntry struct {
+	key      string
+	state    limitState
+	lastSeen time.Time
+	elem     *list.Element
+}
+
+// memoryShard 内存限流器的分片，按最近使用顺序维护键
+type memoryShard struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	lru     *list.List // 队首为最近使用的键
+}
+
+// MemoryRateLimiter 进程内存限流器，每个副本独立计数
+//
+// 键按哈希分布到多个分片以减少锁竞争，键数量达到上限时淘汰最久未使用的键，
+// 后台清理协程定期删除空闲的键；空闲时间超过配额恢复时间的键被删除后不会影响限流结果
+type MemoryRateLimiter struct {
+	algorithm Algorithm
+	opts      MemoryOptions
+	shards    [memoryShards]memoryShard
+	perShard  int // 每个分片的键数量上限，0 表示不限制
+	now       func() time.Time
+
+	evictions atomic.Uint64
+	stop      chan struct{}
+	stopOnce  sync.Once
+	done      chan struct{}
+}
+
+// NewMemoryRateLimiter 创建内存限流器
+// 设置了 IdleTTL 时启动后台清理协程，需要调用 Close 停止
+func NewMemoryRateLimiter(algorithm Algorithm, opts MemoryOptions) *MemoryRateLimiter {
+	rl := &MemoryRateLimiter{
+		algorithm: algorithm,
+		opts:      opts,
+		now:       time.Now,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if opts.MaxEntries > 0 {
+		rl.perShard = (opts.MaxEntries + memoryShards - 1) / memoryShards
+	}
+	for i := range rl.shards {
+		rl.shards[i].entries = make(map[string]*memoryEntry)
+		rl.shards[i].lru = list.New()
+	}
+
+	if opts.IdleTTL > 0 {
+		interval := opts.CleanupInterval
+		if interval <= 0 {
+			interval = opts.IdleTTL
+		}
+		go rl.janitor(interval)
+	} else {
+		close(rl.done)
+	}
+	return rl
+}
+
+// Allow 检查键是否还有配额
+func (rl *MemoryRateLimiter) Allow(_ context.Context, key string, limit Limit, cost int) (Result, error) {
+	now := rl.now()
+	return rl.getOrCreate(key, now, limit).take(now, limit, cost), nil
+}
+
+// getOrCreate 原子地获取或创建键状态，并将键标记为最近使用
+func (rl *MemoryRateLimiter) getOrCreate(key string, now time.Time, limit Limit) limitState {
+	shard := rl.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if entry, ok := shard.entries[key]; ok {
+		entry.lastSeen = now
+		shard.lru.MoveToFront(entry.elem)
+		return entry.state
+	}
+
+	if rl.perShard > 0 && len(shard.entries) >= rl.perShard {
+		oldest := shard.lru.Back()
+		shard.remove(oldest.Value.(*memoryEntry))
+		rl.evictions.Add(1)
+	}
+
+	entry := &memoryEntry{key: key, state: rl.newState(now, limit), lastSeen: now}
+	entry.elem = shard.lru.PushFront(entry)
+	shard.entries[key] = entry
+	return entry.state
+}
+
+// newState 按算法创建键状态
+func (rl *MemoryRateLimiter) newState(now time.Time, limit Limit) limitState {
+	switch rl.algorithm {
+	case AlgorithmGCRA:
+		return &gcraState{}
+	case AlgorithmSlidingWindow:
+		return &slidingLogState{}
+	default:
+		return &TokenBucket{tokens: float64(limit.Burst), lastUpdate: now}
+	}
+}
+
+// shard 返回键所在的分片
+func (rl *MemoryRateLimiter) shard(key string) *memoryShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &rl.shards[h.Sum32()%memoryShards]
+}
+
+// remove 删除键，调用方需持有分片锁
+func (s *memoryShard) remove(entry *memoryEntry) {
+	s.lru.Remove(entry.elem)
+	delete(s.entries, entry.key)
+}
+
+// janitor 定期清理空闲的键，直到 Close 被调用
+func (rl *MemoryRateLimiter) janitor(interval time.Duration) {
+	defer close(rl.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			rl.cleanup(rl.now())
+		case <-rl.stop:
+			return
+		}
+	}
+}
+
+// cleanup 删除空闲时间超过 IdleTTL 的键
+// 每个分片从最久未使用的一端开始检查，遇到未过期的键即可停止
+func (rl *MemoryRateLimiter) cleanup(now time.Time) {
+	deadline := now.Add(-rl.opts.IdleTTL)
+	for i := range rl.shards {
+		shard := &rl.shards[i]
+		shard.mu.Lock()
+		for elem := shard.lru.Back(); elem != nil; {
+			entry := elem.Value.(*memoryEntry)
+			if entry.lastSeen.After(deadline) {
+				break
+			}
+			elem = elem.Prev()
+			shard.remove(entry)
+			rl.evictions.Add(1)
+		}
+		shard.mu.Unlock()
+	}
+}
+
+// Len 返回当前保存的键数量
+func (rl *MemoryRateLimiter) Len() int {
+	n := 0
+	for i := range rl.shards {
+		shard := &rl.shards[i]
+		shard.mu.Lock()
+		n += len(shard.entries)
+		shard.mu.Unlock()
+	}
+	return n
+}
+
+// Evictions 返回因数量上限或空闲而被清理的键总数
+func (rl *MemoryRateLimiter) Evictions() uint64 {
+	return rl.evictions.Load()
+}
+
+// Close 停止后台清理协程，可重复调用
+func (rl *MemoryRateLimiter) Close() error {
+	rl.stopOnce.Do(func() { close(rl.stop) })
+	<-rl.done
+	return nil
+}
@@ -0,0 +1,104 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRateLimiterMaxEntries(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 1}
+	rl := NewMemoryRateLimiter(AlgorithmTokenBucket, MemoryOptions{MaxEntries: 64})
+	defer rl.Close()
+
+	for i := 0; i < 1000; i++ {
+		_, err := rl.Allow(ctx, fmt.Sprintf("10.0.%d.%d", i/256, i%256), limit, 1)
+		assert.NoError(t, err)
+	}
+	assert.LessOrEqual(t, rl.Len(), 64)
+	assert.Equal(t, uint64(1000-rl.Len()), rl.Evictions())
+
+	// 同一分片中淘汰最久未使用的键
+	var keys []string
+	target := rl.shard("client")
+	for i := 0; len(keys) < 2; i++ {
+		if key := fmt.Sprintf("key-%d", i); rl.shard(key) == target {
+			keys = append(keys, key)
+		}
+	}
+	_, _ = rl.Allow(ctx, "client", limit, 1)
+	_, _ = rl.Allow(ctx, keys[0], limit, 1)
+	_, _ = rl.Allow(ctx, "client", limit, 1)
+	_, _ = rl.Allow(ctx, keys[1], limit, 1)
+
+	target.mu.Lock()
+	assert.Contains(t, target.entries, "client")
+	assert.Contains(t, target.entries, keys[1])
+	assert.NotContains(t, target.entries, keys[0])
+	target.mu.Unlock()
+
+	// 仍在内存中的键保留限流状态
+	res, _ := rl.Allow(ctx, "client", limit, 1)
+	assert.False(t, res.Allowed)
+}
+
+func TestMemoryRateLimiterIdleCleanup(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 1}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rl := NewMemoryRateLimiter(AlgorithmGCRA, MemoryOptions{IdleTTL: time.Minute, CleanupInterval: time.Hour})
+	rl.now = func() time.Time { return now }
+	defer rl.Close()
+
+	_, _ = rl.Allow(ctx, "idle", limit, 1)
+	now = now.Add(30 * time.Second)
+	_, _ = rl.Allow(ctx, "active", limit, 1)
+
+	now = now.Add(40 * time.Second)
+	rl.cleanup(now)
+	assert.Equal(t, 1, rl.Len())
+	assert.Equal(t, uint64(1), rl.Evictions())
+
+	now = now.Add(time.Minute)
+	rl.cleanup(now)
+	assert.Equal(t, 0, rl.Len())
+}
+
+func TestMemoryRateLimiterJanitor(t *testing.T) {
+	rl := NewMemoryRateLimiter(AlgorithmTokenBucket, MemoryOptions{IdleTTL: time.Millisecond, CleanupInterval: time.Millisecond})
+	_, _ = rl.Allow(context.Background(), "client", Limit{Rate: 1, Burst: 1}, 1)
+
+	assert.Eventually(t, func() bool { return rl.Len() == 0 }, time.Second, time.Millisecond)
+
+	// Close 停止清理协程且可重复调用
+	assert.NoError(t, rl.Close())
+	assert.NoError(t, rl.Close())
+}
+
+func TestMemoryRateLimiterConcurrentCreate(t *testing.T) {
+	rl := NewMemoryRateLimiter(AlgorithmTokenBucket, MemoryOptions{})
+	defer rl.Close()
+
+	// 并发的首次请求共享同一个令牌桶，允许的请求数不超过突发配额
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, _ := rl.Allow(context.Background(), "client", Limit{Rate: 0.001, Burst: 10}, 1)
+			if res.Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(10), allowed.Load())
+	assert.Equal(t, 1, rl.Len())
+}
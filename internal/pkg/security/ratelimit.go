@@ -1,10 +1,63 @@
 package security
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"sync"
 	"time"
 )
 
+// Algorithm 限流算法
+type Algorithm string
+
+const (
+	// AlgorithmTokenBucket 令牌桶：按速率补充令牌，最多累积 Burst 个
+	AlgorithmTokenBucket Algorithm = "token_bucket"
+	// AlgorithmGCRA 通用信元速率算法：只需保存理论到达时间，效果等同于令牌桶
+	AlgorithmGCRA Algorithm = "gcra"
+	// AlgorithmSlidingWindow 滑动窗口日志：任意 Burst/Rate 时长内最多允许 Burst 个请求
+	AlgorithmSlidingWindow Algorithm = "sliding_window"
+)
+
+// ParseAlgorithm 解析限流算法名称，空值表示令牌桶
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch a := Algorithm(name); a {
+	case "":
+		return AlgorithmTokenBucket, nil
+	case AlgorithmTokenBucket, AlgorithmGCRA, AlgorithmSlidingWindow:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown rate limit algorithm %q", name)
+	}
+}
+
+// Limit 限流配额
+type Limit struct {
+	Rate  float64 // 每秒允许的请求数
+	Burst int     // 允许的突发请求数
+}
+
+// window 滑动窗口的时长
+func (l Limit) window() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// Result 限流结果
+type Result struct {
+	Allowed    bool          // 是否允许请求通过
+	Limit      int           // 配额上限
+	Remaining  int           // 剩余可用请求数
+	ResetAfter time.Duration // 配额完全恢复所需的时间
+	RetryAfter time.Duration // 被拒绝时需要等待的时间，允许时为0
+}
+
+// RateLimiter 限流后端
+// cost 为本次请求消耗的配额，同一个键在不同调用中可以使用不同的配额
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit Limit, cost int) (Result, error)
+}
+
 // TokenBucket 实现令牌桶算法
 type TokenBucket struct {
 	rate       float64    // 令牌产生速率
@@ -26,59 +79,105 @@ func NewTokenBucket(rate float64, capacity float64) *TokenBucket {
 
 // Allow 检查是否允许请求通过
 func (tb *TokenBucket) Allow() bool {
+	return tb.take(time.Now(), Limit{Rate: tb.rate, Burst: int(tb.capacity)}, 1).Allowed
+}
+
+// take 按配额补充令牌并尝试取出 cost 个令牌
+func (tb *TokenBucket) take(now time.Time, limit Limit, cost int) Result {
 	tb.mu.Lock()
 	defer tb.mu.Unlock()
 
-	now := time.Now()
+	capacity := float64(limit.Burst)
 	elapsed := now.Sub(tb.lastUpdate).Seconds()
-	tb.tokens = min(tb.capacity, tb.tokens+elapsed*tb.rate)
+	tb.tokens = min(capacity, tb.tokens+elapsed*limit.Rate)
 	tb.lastUpdate = now
 
-	if tb.tokens >= 1 {
-		tb.tokens--
-		return true
+	res := Result{Limit: limit.Burst}
+	if tb.tokens >= float64(cost) {
+		tb.tokens -= float64(cost)
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((float64(cost) - tb.tokens) / limit.Rate)
 	}
-	return false
+	res.Remaining = int(tb.tokens)
+	res.ResetAfter = seconds((capacity - tb.tokens) / limit.Rate)
+	return res
 }
 
-// RateLimiter 限流器接口
-type RateLimiter interface {
-	Allow() bool
+// gcraState GCRA 算法的键状态
+type gcraState struct {
+	mu  sync.Mutex
+	tat time.Time // 理论到达时间
 }
 
-// IPRateLimiter IP限流器
-type IPRateLimiter struct {
-	limiters sync.Map
-	rate     float64
-	capacity float64
-}
+// take 计算理论到达时间，早于允许时间的请求被拒绝
+func (s *gcraState) take(now time.Time, limit Limit, cost int) Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emission := time.Duration(float64(time.Second) / limit.Rate)
+	tat := s.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(emission * time.Duration(cost))
+	allowAt := newTat.Add(-emission * time.Duration(limit.Burst))
 
-// NewIPRateLimiter 创建一个新的IP限流器
-func NewIPRateLimiter(rate float64, capacity float64) *IPRateLimiter {
-	return &IPRateLimiter{
-		rate:     rate,
-		capacity: capacity,
+	res := Result{Limit: limit.Burst}
+	diff := now.Sub(allowAt)
+	if diff < 0 {
+		res.RetryAfter = -diff
+		res.ResetAfter = tat.Sub(now)
+		return res
 	}
+	s.tat = newTat
+	res.Allowed = true
+	res.Remaining = int(diff / emission)
+	res.ResetAfter = newTat.Sub(now)
+	return res
 }
 
-// GetLimiter 获取指定IP的限流器
-func (rl *IPRateLimiter) GetLimiter(ip string) RateLimiter {
-	limiter, exists := rl.limiters.Load(ip)
-	if !exists {
-		limiter = NewTokenBucket(rl.rate, rl.capacity)
-		rl.limiters.Store(ip, limiter)
+// slidingLogState 滑动窗口日志算法的键状态
+type slidingLogState struct {
+	mu      sync.Mutex
+	entries []time.Time // 窗口内每个请求的时间，按时间升序
+}
+
+// take 清理窗口外的记录，窗口内记录数不超过配额时记录本次请求
+func (s *slidingLogState) take(now time.Time, limit Limit, cost int) Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	window := limit.window()
+	start := now.Add(-window)
+	i := 0
+	for i < len(s.entries) && !s.entries[i].After(start) {
+		i++
 	}
-	rateLimiter, ok := limiter.(RateLimiter)
-	if !ok {
-		return nil
+	s.entries = s.entries[i:]
+
+	res := Result{Limit: limit.Burst}
+	used := len(s.entries)
+	if used+cost <= limit.Burst {
+		for j := 0; j < cost; j++ {
+			s.entries = append(s.entries, now)
+		}
+		res.Allowed = true
+		used += cost
+	} else if need := used + cost - limit.Burst; need <= used {
+		// 需要等到最早的 need 条记录移出窗口
+		res.RetryAfter = s.entries[need-1].Add(window).Sub(now)
+	} else {
+		res.RetryAfter = window
+	}
+	res.Remaining = max(limit.Burst-used, 0)
+	if used > 0 {
+		res.ResetAfter = s.entries[len(s.entries)-1].Add(window).Sub(now)
 	}
-	return rateLimiter
+	return res
 }
 
-// min returns the smaller of x or y.
-func min(x, y float64) float64 {
-	if x < y {
-		return x
-	}
-	return y
+// seconds 将秒数转换为时长，向上取整到毫秒
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s*1000)) * time.Millisecond
 }
@@ -0,0 +1,112 @@
+package security
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// backend 测试用的限流后端及其时钟
+type backend struct {
+	limiter RateLimiter
+	advance func(time.Duration)
+}
+
+func newMemoryBackend(algorithm Algorithm) backend {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rl := NewMemoryRateLimiter(algorithm, MemoryOptions{})
+	rl.now = func() time.Time { return now }
+	return backend{limiter: rl, advance: func(d time.Duration) { now = now.Add(d) }}
+}
+
+func newRedisBackend(t *testing.T, algorithm Algorithm) backend {
+	mr := miniredis.RunT(t)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mr.SetTime(now)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return backend{
+		limiter: NewRedisRateLimiter(client, algorithm, "ratelimit:"),
+		advance: func(d time.Duration) {
+			now = now.Add(d)
+			mr.SetTime(now)
+			mr.FastForward(d)
+		},
+	}
+}
+
+func TestRateLimiters(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 10, Burst: 5}
+	algorithms := []Algorithm{AlgorithmTokenBucket, AlgorithmGCRA, AlgorithmSlidingWindow}
+
+	for _, algorithm := range algorithms {
+		backends := map[string]func() backend{
+			"memory": func() backend { return newMemoryBackend(algorithm) },
+			"redis":  func() backend { return newRedisBackend(t, algorithm) },
+		}
+		for name, newBackend := range backends {
+			t.Run(string(algorithm)+"/"+name, func(t *testing.T) {
+				b := newBackend()
+
+				// 突发配额内全部通过，剩余配额递减
+				for i := 0; i < limit.Burst; i++ {
+					res, err := b.limiter.Allow(ctx, "client", limit, 1)
+					assert.NoError(t, err)
+					assert.True(t, res.Allowed)
+					assert.Equal(t, limit.Burst, res.Limit)
+					assert.Equal(t, limit.Burst-i-1, res.Remaining)
+				}
+
+				// 超出配额被拒绝，并给出重试时间
+				res, err := b.limiter.Allow(ctx, "client", limit, 1)
+				assert.NoError(t, err)
+				assert.False(t, res.Allowed)
+				assert.Equal(t, 0, res.Remaining)
+				assert.Greater(t, res.RetryAfter, time.Duration(0))
+				assert.LessOrEqual(t, res.RetryAfter, 500*time.Millisecond)
+				assert.Greater(t, res.ResetAfter, time.Duration(0))
+
+				// 其他键不受影响
+				res, err = b.limiter.Allow(ctx, "other", limit, 1)
+				assert.NoError(t, err)
+				assert.True(t, res.Allowed)
+
+				// 等待重试时间后恢复
+				b.advance(500 * time.Millisecond)
+				res, err = b.limiter.Allow(ctx, "client", limit, 1)
+				assert.NoError(t, err)
+				assert.True(t, res.Allowed)
+
+				// 消耗超过剩余配额的请求被拒绝
+				res, err = b.limiter.Allow(ctx, "client", limit, limit.Burst)
+				assert.NoError(t, err)
+				assert.False(t, res.Allowed)
+
+				// 配额完全恢复后可以一次消耗全部配额
+				b.advance(time.Second)
+				res, err = b.limiter.Allow(ctx, "client", limit, limit.Burst)
+				assert.NoError(t, err)
+				assert.True(t, res.Allowed)
+				assert.Equal(t, 0, res.Remaining)
+			})
+		}
+	}
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	a, err := ParseAlgorithm("")
+	assert.NoError(t, err)
+	assert.Equal(t, AlgorithmTokenBucket, a)
+
+	a, err = ParseAlgorithm("gcra")
+	assert.NoError(t, err)
+	assert.Equal(t, AlgorithmGCRA, a)
+
+	_, err = ParseAlgorithm("leaky")
+	assert.Error(t, err)
+}
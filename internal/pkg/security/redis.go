@@ -0,0 +1,117 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript GCRA 限流脚本
+// 时间取自 Redis 服务器，避免各副本时钟偏差；时间单位为毫秒
+// KEYS[1] 限流键；ARGV: rate（每秒请求数）、burst、cost
+// 返回 {allowed, remaining, retry_after_ms, reset_after_ms}
+var gcraScript = redis.NewScript(`
+if redis.replicate_commands then redis.replicate_commands() end
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + tonumber(t[2]) / 1000
+local emission = 1000 / rate
+
+local tat = tonumber(redis.call("GET", KEYS[1]))
+if not tat or tat < now then
+  tat = now
+end
+local new_tat = tat + emission * cost
+local diff = now - (new_tat - emission * burst)
+if diff < 0 then
+  return {0, 0, math.ceil(-diff), math.ceil(tat - now)}
+end
+
+local reset_after = new_tat - now
+redis.call("SET", KEYS[1], string.format("%.3f", new_tat), "PX", math.ceil(reset_after))
+return {1, math.floor(diff / emission), 0, math.ceil(reset_after)}
+`)
+
+// slidingWindowScript 滑动窗口日志限流脚本，每个请求在有序集合中保存一条记录
+// KEYS[1] 限流键；ARGV: burst、window（毫秒）、cost
+// 返回 {allowed, remaining, retry_after_ms, reset_after_ms}
+var slidingWindowScript = redis.NewScript(`
+if redis.replicate_commands then redis.replicate_commands() end
+local burst = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local used = redis.call("ZCARD", KEYS[1])
+
+if used + cost > burst then
+  local retry_after = window
+  local need = used + cost - burst
+  if need <= used then
+    local entry = redis.call("ZRANGE", KEYS[1], need - 1, need - 1, "WITHSCORES")
+    retry_after = tonumber(entry[2]) + window - now
+  end
+  local reset_after = 0
+  if used > 0 then
+    local last = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+    reset_after = tonumber(last[2]) + window - now
+  end
+  return {0, math.max(burst - used, 0), retry_after, reset_after}
+end
+
+-- 同一毫秒内已有的记录序号不会超过 used，因此成员名唯一
+for i = 1, cost do
+  redis.call("ZADD", KEYS[1], now, now .. ":" .. (used + i))
+end
+redis.call("PEXPIRE", KEYS[1], window)
+return {1, burst - used - cost, 0, window}
+`)
+
+// RedisRateLimiter 基于 Redis 的限流器，所有副本共享计数
+// 令牌桶与 GCRA 的效果相同，因此令牌桶算法在 Redis 中使用 GCRA 实现
+type RedisRateLimiter struct {
+	client    redis.Scripter
+	algorithm Algorithm
+	prefix    string
+}
+
+// NewRedisRateLimiter 创建 Redis 限流器，prefix 为限流键的前缀
+func NewRedisRateLimiter(client redis.Scripter, algorithm Algorithm, prefix string) *RedisRateLimiter {
+	return &RedisRateLimiter{client: client, algorithm: algorithm, prefix: prefix}
+}
+
+// Allow 检查键是否还有配额
+func (rl *RedisRateLimiter) Allow(ctx context.Context, key string, limit Limit, cost int) (Result, error) {
+	var (
+		values []int64
+		err    error
+	)
+	keys := []string{rl.prefix + key}
+	switch rl.algorithm {
+	case AlgorithmSlidingWindow:
+		window := limit.window().Milliseconds()
+		values, err = slidingWindowScript.Run(ctx, rl.client, keys, limit.Burst, window, cost).Int64Slice()
+	default:
+		values, err = gcraScript.Run(ctx, rl.client, keys, limit.Rate, limit.Burst, cost).Int64Slice()
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("rate limit %s: %w", key, err)
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("rate limit %s: unexpected script result %v", key, values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit.Burst,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}